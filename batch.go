@@ -0,0 +1,114 @@
+package hexid
+
+import (
+	"slices"
+	"sync/atomic"
+)
+
+// Largest number of IDs that are reserved at once.
+const maxBatch = 1 << 20
+
+// Fills dst with IDs based on current time, reserving their sequence numbers at once. IDs that
+// don't fit within the current millisecond borrow the following ones. Panics if TryFill would
+// return an error.
+func (g *Generator) Fill(dst []ID) {
+	if err := g.TryFill(dst); err != nil {
+		panic(err)
+	}
+}
+
+// Appends n IDs based on current time to dst, and returns the extended slice. See Fill.
+func (g *Generator) GenerateN(dst []ID, n int) []ID {
+	dst = slices.Grow(dst, n)
+	g.Fill(dst[len(dst) : len(dst)+n])
+	return dst[:len(dst)+n]
+}
+
+// Fills dst with IDs based on current time, reserving their sequence numbers at once. IDs that
+// don't fit within the current millisecond borrow the following ones, unless the policy is
+// OverflowError, in which case it fails with ErrSequenceExhausted. See TryID.
+func (g *Generator) TryFill(dst []ID) error {
+	if g.lease != nil && !g.lease.Valid() {
+		return ErrLeaseLost
+	}
+
+	for len(dst) > 0 {
+		batch := dst[:min(len(dst), maxBatch)]
+		s, wait, err := g.advance(g.state, &g.monitor, uint32(len(batch)), g.limit())
+
+		if err != nil {
+			return err
+		}
+
+		if wait > 0 {
+			g.sleep(wait)
+			continue
+		}
+
+		g.state = s
+		g.stats.issue(s, uint32(len(batch)), g.limit())
+		s.fill(batch, g.Layout(), g.node, g.base, g.limit())
+		dst = dst[len(batch):]
+	}
+
+	return nil
+}
+
+// Atomically fills dst with IDs based on current time, reserving their sequence numbers in a
+// single atomic operation. IDs that don't fit within the current millisecond borrow the following
+// ones. Panics if TryFill would return an error.
+func (g *AtomicGenerator) Fill(dst []ID) {
+	if err := g.TryFill(dst); err != nil {
+		panic(err)
+	}
+}
+
+// Atomically appends n IDs based on current time to dst, and returns the extended slice. See Fill.
+func (g *AtomicGenerator) GenerateN(dst []ID, n int) []ID {
+	dst = slices.Grow(dst, n)
+	g.Fill(dst[len(dst) : len(dst)+n])
+	return dst[:len(dst)+n]
+}
+
+// Atomically fills dst with IDs based on current time, reserving their sequence numbers in a
+// single atomic operation. IDs that don't fit within the current millisecond borrow the following
+// ones, unless the policy is OverflowError, in which case it fails with ErrSequenceExhausted. See
+// TryID.
+func (g *AtomicGenerator) TryFill(dst []ID) error {
+	if g.lease != nil && !g.lease.Valid() {
+		return ErrLeaseLost
+	}
+
+	for len(dst) > 0 {
+		batch := dst[:min(len(dst), maxBatch)]
+		cur := state(atomic.LoadUint64(&g.state))
+		s, wait, err := g.advance(cur, &g.monitor, uint32(len(batch)), g.limit())
+
+		if err != nil {
+			return err
+		}
+
+		if wait > 0 {
+			g.sleep(wait)
+			continue
+		}
+
+		if atomic.CompareAndSwapUint64(&g.state, uint64(cur), uint64(s)) {
+			g.stats.issue(s, uint32(len(batch)), g.limit())
+			s.fill(batch, g.Layout(), g.node, g.base, g.limit())
+			dst = dst[len(batch):]
+		}
+	}
+
+	return nil
+}
+
+// Atomically fills dst with IDs based on current time. Thread-safe. See (*AtomicGenerator).Fill.
+func Fill(dst []ID) {
+	mustGlobal().Fill(dst)
+}
+
+// Atomically appends n IDs based on current time to dst, and returns the extended slice. Thread-safe.
+func GenerateN(dst []ID, n int) []ID {
+	return mustGlobal().GenerateN(dst, n)
+}
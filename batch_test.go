@@ -0,0 +1,108 @@
+package hexid
+
+import (
+	"testing"
+	"time"
+)
+
+func TestAtomicGeneratorFill(t *testing.T) {
+	ts := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	g, _ := New(WithClock(NewManualClock(ts)), WithSequence(0))
+
+	first := g.ID()
+	ids := g.GenerateN(nil, 2*seqLimit)
+	last := g.ID()
+
+	if len(ids) != 2*seqLimit {
+		t.Fatalf("expected %d IDs, got %d", 2*seqLimit, len(ids))
+	}
+
+	seen := map[ID]struct{}{first: {}, last: {}}
+
+	for _, id := range ids {
+		if _, ok := seen[id]; ok {
+			t.Fatalf("duplicate ID %s", id)
+		}
+
+		seen[id] = struct{}{}
+	}
+
+	// The batch spills over into the two following milliseconds
+	if got, want := ids[seqLimit-2].Time(), ts; !got.Equal(want) {
+		t.Fatalf("expected %v, got %v", want, got)
+	}
+
+	if got, want := ids[seqLimit-1].Time(), ts.Add(time.Millisecond); !got.Equal(want) {
+		t.Fatalf("expected %v, got %v", want, got)
+	}
+
+	if got, want := last.Time(), ts.Add(2*time.Millisecond); !got.Equal(want) || last.Seq() != 1 {
+		t.Fatalf("expected seq 1 at %v, got seq %d at %v", want, last.Seq(), got)
+	}
+}
+
+func TestGeneratorFill(t *testing.T) {
+	ts := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	c := NewManualClock(ts)
+	g, _ := NewGenerator()
+	g.SetClock(c)
+
+	a := make([]ID, 100)
+	b := make([]ID, 100)
+
+	for i := range a {
+		a[i] = g.ID()
+	}
+
+	g.state = 0
+	g.Fill(b)
+
+	if a[0] != b[0] || a[99] != b[99] {
+		t.Fatalf("expected Fill to generate the same IDs as ID")
+	}
+}
+
+func TestFillOverflowPolicy(t *testing.T) {
+	ts := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	c := NewManualClock(ts)
+	g, _ := New(WithClock(c), WithOverflowPolicy(OverflowError))
+
+	if err := g.TryFill(make([]ID, seqLimit+1)); err != ErrSequenceExhausted {
+		t.Fatalf("expected ErrSequenceExhausted, got %v", err)
+	}
+
+	if err := g.TryFill(make([]ID, seqLimit)); err != nil {
+		t.Fatal(err)
+	}
+
+	g, _ = New(WithClock(c))
+	g.Fill(make([]ID, seqLimit))
+	ids := g.GenerateN(nil, 10)
+
+	// The current millisecond is exhausted, so the generator waits for the next one
+	if got, want := ids[0].Time(), ts.Add(time.Millisecond); !got.Equal(want) {
+		t.Fatalf("expected %v, got %v", want, got)
+	}
+}
+
+func BenchmarkFill(b *testing.B) {
+	ids := make([]ID, 1000)
+
+	b.Run("ID", func(b *testing.B) {
+		g, _ := New()
+
+		for b.Loop() {
+			for i := range ids {
+				ids[i] = g.ID()
+			}
+		}
+	})
+
+	b.Run("Fill", func(b *testing.B) {
+		g, _ := New()
+
+		for b.Loop() {
+			g.Fill(ids)
+		}
+	})
+}
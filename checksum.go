@@ -0,0 +1,75 @@
+package hexid
+
+import "errors"
+
+// ErrChecksum is the cause of a ParseError when the check character of a checked encoding doesn't
+// match the rest of it, which is most likely due to a typo.
+var ErrChecksum = errors.New("checksum mismatch")
+
+// Appends the text encoding of the ID to b, followed by a check character, with the coder set by
+// SetCoder. The check character catches any single mistyped character, and most swaps of
+// adjacent characters.
+func (id ID) AppendTextChecked(b []byte) ([]byte, error) {
+	c := getCoder()
+	return c.appendWith(b, id, c.enc, true), nil
+}
+
+// Decodes an ID from its text encoding followed by a check character, with the coder set by
+// SetCoder. Fails with ErrChecksum if the check character doesn't match.
+func ParseChecked(str string) (ID, error) {
+	c := getCoder()
+	return c.decodeWith(str, c.enc, true)
+}
+
+// Returns a copy of the coder that appends a check character to its encodings, and verifies it
+// when decoding. See (ID).AppendTextChecked.
+func (c *Coder) WithChecksum() *Coder {
+	cp := *c
+	cp.checked = true
+	return &cp
+}
+
+// Whether the coder appends a check character to its encodings.
+func (c *Coder) Checked() bool {
+	return c.checked
+}
+
+// Computes the check character of an encoded value with the Luhn mod N algorithm, where N is the
+// size of the encoding's alphabet.
+func (e Encoding) check(b []byte) byte {
+	alphabet, table := e.alphabet()
+	n := len(alphabet)
+	factor, sum := 2, 0
+
+	for i := len(b) - 1; i >= 0; i-- {
+		addend := factor * int(table[b[i]])
+		sum += addend/n + addend%n
+		factor = 3 - factor
+	}
+
+	return alphabet[(n-sum%n)%n]
+}
+
+// Verifies the check character of an encoded value, and returns the value without it. Errors are
+// like those of decode.
+func (e Encoding) verify(str string) (string, int, error) {
+	if len(str) != e.Len()+1 {
+		return "", -1, ErrInvalidLength
+	}
+
+	alphabet, table := e.alphabet()
+
+	for i := range len(str) {
+		if table[str[i]] == 0xff {
+			return "", i, ErrInvalidCharacter
+		}
+	}
+
+	str, check := str[:len(str)-1], table[str[len(str)-1]]
+
+	if alphabet[check] != e.check(s2b(str)) {
+		return "", -1, ErrChecksum
+	}
+
+	return str, -1, nil
+}
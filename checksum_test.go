@@ -0,0 +1,107 @@
+package hexid
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"testing"
+)
+
+func ExampleParseChecked() {
+	id := ID(0x1234567890abcdef)
+	b, _ := id.AppendTextChecked(nil)
+	fmt.Println(string(b))
+
+	_, err := ParseChecked("bee99827fa22a2d17") // Mistyped c as d
+	fmt.Println(err)
+
+	// Output:
+	// bee99827fa22a2c17
+	// invalid ID "bee99827fa22a2d17": checksum mismatch, expected checked hex
+}
+
+func TestChecksumSubstitutions(t *testing.T) {
+	id := Generate()
+
+	for _, e := range []Encoding{Hex, Base32, Base58, Base62} {
+		c, _ := DefaultCoder.WithEncoding(e)
+		c = c.WithChecksum()
+		str := c.Encode(id)
+		alphabet, _ := e.alphabet()
+
+		if got, err := c.Decode(str); err != nil || got != id {
+			t.Fatalf("expected %d from %s %q, got %d (%v)", id, e, str, got, err)
+		}
+
+		for i := range len(str) {
+			for j := range len(alphabet) {
+				if alphabet[j] == str[i] || (e == Base32 && base32Decode[alphabet[j]] == base32Decode[str[i]]) {
+					continue
+				}
+
+				typo := str[:i] + alphabet[j:j+1] + str[i+1:]
+
+				if _, err := c.Decode(typo); !errors.Is(err, ErrChecksum) {
+					t.Fatalf("expected ErrChecksum for %s %q, got %v", e, typo, err)
+				}
+			}
+		}
+	}
+}
+
+func TestChecksumTranspositions(t *testing.T) {
+	c := DefaultCoder.WithChecksum()
+	var total, detected int
+
+	for range 100 {
+		str := c.Encode(Generate())
+
+		for i := range len(str) - 1 {
+			if str[i] == str[i+1] {
+				continue
+			}
+
+			typo := str[:i] + str[i+1:i+2] + str[i:i+1] + str[i+2:]
+			total++
+
+			if _, err := c.Decode(typo); errors.Is(err, ErrChecksum) {
+				detected++
+			}
+		}
+	}
+
+	if float64(detected)/float64(total) < 0.9 {
+		t.Fatalf("expected at least 90%% of transpositions to be detected, got %d of %d", detected, total)
+	}
+}
+
+func TestCheckedCoder(t *testing.T) {
+	c := DefaultCoder.WithChecksum()
+	SetCoder(c)
+	t.Cleanup(func() { SetCoder(nil) })
+
+	id := Generate()
+	b, _ := json.Marshal(id)
+
+	if len(b) != 19 {
+		t.Fatalf("expected 17 characters, got %s", b)
+	}
+
+	var got ID
+
+	if err := json.Unmarshal(b, &got); err != nil || got != id {
+		t.Fatalf("expected %d, got %d (%v)", id, got, err)
+	}
+
+	if b[1] = b[1] ^ 1; json.Unmarshal(b, &got) == nil {
+		t.Fatalf("expected a typo in %s to fail", b)
+	}
+
+	if _, err := IDFromString(DefaultCoder.Encode(id)); err == nil {
+		t.Fatal("expected an unchecked string to fail")
+	}
+
+	if _, err := c.SQL(); err == nil {
+		t.Fatal("expected no SQL for a checked coder")
+	}
+}
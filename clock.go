@@ -0,0 +1,79 @@
+package hexid
+
+import (
+	"sync/atomic"
+	"time"
+)
+
+// Clock is the source of time for generators. Sleep is used whenever a generator has to wait
+// for the clock, e.g. when the sequence of the current millisecond is exhausted.
+type Clock interface {
+	Now() time.Time
+	Sleep(d time.Duration)
+}
+
+var (
+	_ Clock = WallClock{}
+	_ Clock = (*ManualClock)(nil)
+	_ Clock = monotonicClock{}
+)
+
+// WallClock is the system clock, and the default clock of all generators.
+type WallClock struct{}
+
+func (WallClock) Now() time.Time        { return time.Now() }
+func (WallClock) Sleep(d time.Duration) { time.Sleep(d) }
+
+// Returns the wall clock if c is nil, so that zero-value generators keep working.
+func orWallClock(c Clock) Clock {
+	if c == nil {
+		return WallClock{}
+	}
+
+	return c
+}
+
+// A clock that anchors to the wall clock once, and then only advances with the monotonic clock.
+// This makes it immune to any adjustments of the system clock, at the cost of slowly drifting
+// from it.
+func NewMonotonicClock() Clock {
+	return monotonicClock{start: time.Now()}
+}
+
+type monotonicClock struct {
+	start time.Time
+}
+
+func (c monotonicClock) Now() time.Time        { return c.start.Add(time.Since(c.start)) }
+func (c monotonicClock) Sleep(d time.Duration) { time.Sleep(d) }
+
+// ManualClock is a thread-safe clock that only moves when told to, and is meant for tests. Sleeping
+// advances the clock instead of blocking, so that waiting generators always make progress.
+type ManualClock struct {
+	ns int64
+}
+
+// Create a manual clock that is frozen at the provided time.
+func NewManualClock(t time.Time) *ManualClock {
+	return &ManualClock{ns: t.UnixNano()}
+}
+
+// Now returns the current time of the clock.
+func (c *ManualClock) Now() time.Time {
+	return time.Unix(0, atomic.LoadInt64(&c.ns))
+}
+
+// Sleep advances the clock by d.
+func (c *ManualClock) Sleep(d time.Duration) {
+	c.Advance(d)
+}
+
+// Set moves the clock to the provided time, which might be before the current time.
+func (c *ManualClock) Set(t time.Time) {
+	atomic.StoreInt64(&c.ns, t.UnixNano())
+}
+
+// Advance moves the clock by d. A negative duration moves the clock backwards.
+func (c *ManualClock) Advance(d time.Duration) {
+	atomic.AddInt64(&c.ns, int64(d))
+}
@@ -0,0 +1,144 @@
+package hexid
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestManualClock(t *testing.T) {
+	ts := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	c := NewManualClock(ts)
+
+	if !c.Now().Equal(ts) {
+		t.Fatalf("expected %v, got %v", ts, c.Now())
+	}
+
+	c.Advance(time.Second)
+	c.Sleep(time.Second)
+
+	if want := ts.Add(2 * time.Second); !c.Now().Equal(want) {
+		t.Fatalf("expected %v, got %v", want, c.Now())
+	}
+
+	c.Advance(-time.Minute)
+
+	if want := ts.Add(-58 * time.Second); !c.Now().Equal(want) {
+		t.Fatalf("expected %v, got %v", want, c.Now())
+	}
+}
+
+func TestMonotonicClock(t *testing.T) {
+	c := NewMonotonicClock()
+	a := c.Now()
+	b := c.Now()
+
+	if b.Before(a) {
+		t.Fatalf("monotonic clock moved backwards: %v -> %v", a, b)
+	}
+
+	if d := time.Since(a); d < 0 || d > time.Second {
+		t.Fatalf("monotonic clock is %v off the wall clock", d)
+	}
+}
+
+func TestGeneratorClock(t *testing.T) {
+	ts := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	c := NewManualClock(ts)
+	g, _ := NewGenerator()
+	g.SetClock(c)
+
+	if got := g.ID().Time(); !got.Equal(ts) {
+		t.Fatalf("expected %v, got %v", ts, got)
+	}
+
+	c.Advance(1500 * time.Millisecond)
+
+	if got, want := g.ID().Time(), ts.Add(1500*time.Millisecond); !got.Equal(want) {
+		t.Fatalf("expected %v, got %v", want, got)
+	}
+}
+
+func TestGeneratorClockExhaustion(t *testing.T) {
+	ts := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	c := NewManualClock(ts)
+	g, _ := NewAtomicGenerator()
+	g.SetClock(c)
+
+	for range seqLimit {
+		g.ID()
+	}
+
+	// The clock is frozen, so the generator must sleep until the next millisecond
+	if got, want := g.ID().Time(), ts.Add(time.Millisecond); !got.Equal(want) {
+		t.Fatalf("expected %v, got %v", want, got)
+	}
+
+	if got, want := c.Now(), ts.Add(time.Millisecond); !got.Equal(want) {
+		t.Fatalf("expected the clock to be advanced to %v, got %v", want, got)
+	}
+}
+
+func TestGeneratorClockRegression(t *testing.T) {
+	ts := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	t.Run("Logical", func(t *testing.T) {
+		c := NewManualClock(ts)
+		g, _ := NewAtomicGenerator()
+		g.SetClock(c)
+
+		id1 := g.ID()
+		c.Advance(-time.Second)
+		id2 := g.ID()
+
+		if id2.Time() != id1.Time() {
+			t.Fatalf("expected the last seen millisecond %v, got %v", id1.Time(), id2.Time())
+		}
+
+		if id1 == id2 {
+			t.Fatalf("duplicate ID %s", id1)
+		}
+
+		if got := g.Regressions(); got != 1 {
+			t.Fatalf("expected 1 regression, got %d", got)
+		}
+	})
+
+	t.Run("Wait", func(t *testing.T) {
+		c := NewManualClock(ts)
+		g, _ := NewGenerator()
+		g.SetClock(c)
+		_ = g.SetRegressionPolicy(RegressionWait)
+
+		g.ID()
+		c.Advance(-time.Second)
+
+		if got := g.ID().Time(); !got.Equal(ts) {
+			t.Fatalf("expected %v, got %v", ts, got)
+		}
+
+		if !c.Now().Equal(ts) {
+			t.Fatalf("expected the generator to wait until %v, got %v", ts, c.Now())
+		}
+	})
+
+	t.Run("Error", func(t *testing.T) {
+		c := NewManualClock(ts)
+		g, _ := NewGenerator()
+		g.SetClock(c)
+		_ = g.SetRegressionPolicy(RegressionError)
+
+		g.ID()
+		c.Advance(-time.Second)
+
+		if _, err := g.TryID(); !errors.Is(err, ErrClockMovedBackwards) {
+			t.Fatalf("expected ErrClockMovedBackwards, got %v", err)
+		}
+
+		c.Set(ts)
+
+		if _, err := g.TryID(); err != nil {
+			t.Fatalf("expected the generator to recover, got %v", err)
+		}
+	})
+}
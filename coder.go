@@ -0,0 +1,213 @@
+package hexid
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"sync/atomic"
+)
+
+// A Coder scrambles IDs into their text encoding and back, which is 16 hex characters unless
+// changed with WithEncoding. The scrambled value is the ID multiplied by an odd multiplier and
+// XOR:ed with a mask, both modulo 2^64, which makes the encoding of consecutive IDs look unrelated.
+// Coders are safe for concurrent use.
+//
+// Note that scrambling is not encryption. A keyed coder hides the fields of IDs from the casual
+// observer, but its key can be recovered from enough known pairs of IDs and encodings. Use
+// NewFeistelCoder for IDs that must not leak anything.
+type Coder struct {
+	mul     uint64
+	inv     uint64
+	xor     uint64
+	feistel *feistel
+	enc     Encoding
+	checked bool
+}
+
+// The coder of String, IDFromString, and JSON and text marshalling, unless changed with SetCoder.
+var DefaultCoder = &Coder{mul: multiplier, inv: invMultiplier}
+
+var coder atomic.Pointer[Coder]
+
+func init() {
+	coder.Store(DefaultCoder)
+}
+
+// Create a coder with a multiplier and mask derived from a secret key, which must be shared by
+// everything that encodes or decodes IDs.
+func NewCoder(key []byte) (*Coder, error) {
+	if len(key) == 0 {
+		return nil, errors.New("key must not be empty")
+	}
+
+	mac := hmac.New(sha256.New, key)
+	mac.Write([]byte("hexid coder"))
+	sum := mac.Sum(nil)
+
+	mul := binary.BigEndian.Uint64(sum[0:8]) | 1
+
+	return &Coder{
+		mul: mul,
+		inv: inverse(mul),
+		xor: binary.BigEndian.Uint64(sum[8:16]),
+	}, nil
+}
+
+// Set the coder of String, IDFromString, and JSON and text marshalling. Should be set once at
+// startup, before any IDs are encoded. Passing nil restores DefaultCoder.
+func SetCoder(c *Coder) {
+	if c == nil {
+		c = DefaultCoder
+	}
+
+	coder.Store(c)
+}
+
+func getCoder() *Coder {
+	return coder.Load()
+}
+
+// Returns a copy of the coder that uses the provided text encoding.
+func (c *Coder) WithEncoding(e Encoding) (*Coder, error) {
+	if err := e.validate(); err != nil {
+		return nil, err
+	}
+
+	cp := *c
+	cp.enc = e
+	return &cp, nil
+}
+
+// Text encoding of the coder.
+func (c *Coder) Encoding() Encoding {
+	return c.enc
+}
+
+// Encode an ID into its text encoding.
+func (c *Coder) Encode(id ID) string {
+	return b2s(c.AppendEncode(make([]byte, 0, c.enc.Len()+1), id))
+}
+
+// Append the text encoding of an ID to b, and return the extended slice.
+func (c *Coder) AppendEncode(b []byte, id ID) []byte {
+	return c.appendEncoding(b, id, c.enc)
+}
+
+// Decode an ID from its text encoding.
+func (c *Coder) Decode(str string) (ID, error) {
+	return c.decodeEncoding(str, c.enc)
+}
+
+func (c *Coder) appendEncoding(b []byte, id ID, e Encoding) []byte {
+	return c.appendWith(b, id, e, c.checked)
+}
+
+func (c *Coder) decodeEncoding(str string, e Encoding) (ID, error) {
+	return c.decodeWith(str, e, c.checked)
+}
+
+func (c *Coder) appendWith(b []byte, id ID, e Encoding, checked bool) []byte {
+	b = e.append(b, c.scramble(uint64(id)))
+
+	if checked {
+		b = append(b, e.check(b[len(b)-e.Len():]))
+	}
+
+	return b
+}
+
+func (c *Coder) decodeWith(str string, e Encoding, checked bool) (ID, error) {
+	v, err := c.decodeLenient(str, e, checked)
+
+	if err == nil && v>>63 != 0 {
+		return 0, signBitError(str, e.format(checked))
+	}
+
+	return ID(v), err
+}
+
+// Decodes the unscrambled value of an encoding, without rejecting the sign bit.
+func (c *Coder) decodeLenient(str string, e Encoding, checked bool) (uint64, error) {
+	s := str
+
+	if checked {
+		var off int
+		var err error
+
+		if s, off, err = e.verify(str); err != nil {
+			return 0, &ParseError{Input: str, Offset: off, Format: e.format(checked), Err: err}
+		}
+	}
+
+	v, off, err := e.decode(s)
+
+	if err != nil {
+		return 0, &ParseError{Input: str, Offset: off, Format: e.format(checked), Err: err}
+	}
+
+	return c.unscramble(v), nil
+}
+
+// SQL returns Postgres functions hexid_encode(bigint) and hexid_decode(text) that match the coder.
+// The arithmetic is done in numeric to get the same wrap-around modulo 2^64 as in Go. Fails for
+// coders created with NewFeistelCoder, for other encodings than Hex, and with checksums.
+func (c *Coder) SQL() (string, error) {
+	if c.feistel != nil {
+		return "", errors.New("feistel coders have no SQL counterpart")
+	}
+
+	if c.enc != Hex || c.checked {
+		return "", errors.New("only unchecked hex coders have an SQL counterpart")
+	}
+
+	return fmt.Sprintf(`CREATE OR REPLACE FUNCTION hexid_encode(id bigint)
+RETURNS text AS $$
+  SELECT lpad(to_hex((
+    CASE WHEN v >= 9223372036854775808 THEN v - 18446744073709551616 ELSE v END
+  )::bigint # (%d)), 16, '0')
+  FROM (SELECT (id::numeric * %d) %% 18446744073709551616 AS v) t;
+$$ LANGUAGE sql IMMUTABLE STRICT;
+
+CREATE OR REPLACE FUNCTION hexid_decode(hexid text)
+RETURNS bigint AS $$
+  SELECT (
+    CASE WHEN v >= 9223372036854775808 THEN v - 18446744073709551616 ELSE v END
+  )::bigint
+  FROM (
+    SELECT ((CASE WHEN s < 0 THEN s + 18446744073709551616 ELSE s END) * %d)
+      %% 18446744073709551616 AS v
+    FROM (SELECT (('x' || hexid)::bit(64)::bigint # (%d))::numeric AS s) t
+  ) t;
+$$ LANGUAGE sql IMMUTABLE STRICT;
+`, int64(c.xor), c.mul, c.inv, int64(c.xor)), nil
+}
+
+func (c *Coder) scramble(v uint64) uint64 {
+	if c.feistel != nil {
+		return c.feistel.encrypt(v)
+	}
+
+	return v*c.mul ^ c.xor
+}
+
+func (c *Coder) unscramble(v uint64) uint64 {
+	if c.feistel != nil {
+		return c.feistel.decrypt(v)
+	}
+
+	return (v ^ c.xor) * c.inv
+}
+
+// Multiplicative inverse of an odd number modulo 2^64, by Newton's iteration. Every iteration
+// doubles the number of correct low bits, starting at 3 bits as m*m ≡ 1 (mod 8).
+func inverse(m uint64) uint64 {
+	inv := m
+
+	for range 5 {
+		inv *= 2 - m*inv
+	}
+
+	return inv
+}
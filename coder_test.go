@@ -0,0 +1,79 @@
+package hexid
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestCoderInverse(t *testing.T) {
+	if got := inverse(multiplier); got != invMultiplier {
+		t.Fatalf("expected %x, got %x", invMultiplier, got)
+	}
+
+	for _, m := range []uint64{1, 3, 0xffffffffffffffff, 0x123456789abcdef1} {
+		if m*inverse(m) != 1 {
+			t.Errorf("expected the inverse of %x", m)
+		}
+	}
+}
+
+func TestCoder(t *testing.T) {
+	c1, _ := NewCoder([]byte("secret"))
+	c2, _ := NewCoder([]byte("another secret"))
+	id := Generate()
+
+	if c1.Encode(id) == id.String() || c1.Encode(id) == c2.Encode(id) {
+		t.Fatal("expected every key to encode differently")
+	}
+
+	for _, c := range []*Coder{DefaultCoder, c1, c2} {
+		if got, err := c.Decode(c.Encode(id)); err != nil || got != id {
+			t.Fatalf("expected %d, got %d (%v)", id, got, err)
+		}
+	}
+
+	if again, _ := NewCoder([]byte("secret")); again.Encode(id) != c1.Encode(id) {
+		t.Fatal("expected the same key to encode the same")
+	}
+
+	if _, err := NewCoder(nil); err == nil {
+		t.Fatal("expected an empty key to fail")
+	}
+}
+
+func TestSetCoder(t *testing.T) {
+	c, _ := NewCoder([]byte("secret"))
+	id := Generate()
+	def := id.String()
+
+	SetCoder(c)
+	t.Cleanup(func() { SetCoder(nil) })
+
+	if id.String() != c.Encode(id) {
+		t.Fatalf("expected %s, got %s", c.Encode(id), id.String())
+	}
+
+	if got, err := IDFromString(id.String()); err != nil || got != id {
+		t.Fatalf("expected %d, got %d (%v)", id, got, err)
+	}
+
+	SetCoder(nil)
+
+	if id.String() != def {
+		t.Fatalf("expected %s, got %s", def, id.String())
+	}
+}
+
+func TestDefaultCoderSQL(t *testing.T) {
+	sql, err := DefaultCoder.SQL()
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, want := range []string{"# (0))", "* 7993060983890856527)", "* 3418993122468531375)"} {
+		if !strings.Contains(sql, want) {
+			t.Errorf("expected %q in:\n%s", want, sql)
+		}
+	}
+}
@@ -0,0 +1,261 @@
+package hexid
+
+import (
+	"encoding/binary"
+	"encoding/hex"
+	"fmt"
+	"math/bits"
+)
+
+// An Encoding is a text encoding of the scrambled 64 bits of an ID. All encodings are fixed-width,
+// and their alphabets are in ASCII order, so encoded strings compare like the scrambled values.
+type Encoding uint8
+
+const (
+	Hex    Encoding = iota // 16 lowercase hex characters (default)
+	Base32                 // 13 characters of Crockford's base32, decoded case-insensitively
+	Base58                 // 11 characters of the Bitcoin base58 alphabet
+	Base62                 // 11 alphanumeric characters
+)
+
+const (
+	hexAlphabet    = "0123456789abcdef"
+	base32Alphabet = "0123456789ABCDEFGHJKMNPQRSTVWXYZ"
+	base58Alphabet = "123456789ABCDEFGHJKLMNPQRSTUVWXYZabcdefghijkmnopqrstuvwxyz"
+	base62Alphabet = "0123456789ABCDEFGHIJKLMNOPQRSTUVWXYZabcdefghijklmnopqrstuvwxyz"
+)
+
+var (
+	hexDecode    = decodeTable(hexAlphabet)
+	base32Decode = decodeTable(base32Alphabet)
+	base58Decode = decodeTable(base58Alphabet)
+	base62Decode = decodeTable(base62Alphabet)
+)
+
+func init() {
+	for c := byte('A'); c <= 'F'; c++ {
+		hexDecode[c] = hexDecode[c-'A'+'a']
+	}
+
+	// Crockford's base32 is case-insensitive, and tolerates the most common typos
+	for c, v := range base32Decode {
+		if c >= 'a' && c <= 'z' {
+			base32Decode[c] = base32Decode[c-'a'+'A']
+		} else if v == 0xff {
+			switch c {
+			case 'O':
+				base32Decode[c] = 0
+			case 'I', 'L':
+				base32Decode[c] = 1
+			}
+		}
+	}
+}
+
+func (e Encoding) String() string {
+	switch e {
+	case Hex:
+		return "hex"
+	case Base32:
+		return "base32"
+	case Base58:
+		return "base58"
+	case Base62:
+		return "base62"
+	}
+
+	return fmt.Sprintf("Encoding(%d)", e)
+}
+
+// Length of encoded IDs.
+func (e Encoding) Len() int {
+	switch e {
+	case Base32:
+		return 13
+	case Base58, Base62:
+		return 11
+	}
+
+	return 16
+}
+
+// Alphabet of the encoding, and its decoding table.
+func (e Encoding) alphabet() (string, *[256]byte) {
+	switch e {
+	case Base32:
+		return base32Alphabet, base32Decode
+	case Base58:
+		return base58Alphabet, base58Decode
+	case Base62:
+		return base62Alphabet, base62Decode
+	}
+
+	return hexAlphabet, hexDecode
+}
+
+func (e Encoding) validate() error {
+	switch e {
+	case Hex, Base32, Base58, Base62:
+		return nil
+	}
+
+	return fmt.Errorf("invalid Encoding: %d", e)
+}
+
+// Appends the encoding of v to b.
+func (e Encoding) append(b []byte, v uint64) []byte {
+	switch e {
+	case Base32:
+		var buf [13]byte
+
+		for i := len(buf) - 1; i >= 0; i-- {
+			buf[i] = base32Alphabet[v&31]
+			v >>= 5
+		}
+
+		return append(b, buf[:]...)
+
+	case Base58:
+		return appendBase(b, v, base58Alphabet)
+
+	case Base62:
+		return appendBase(b, v, base62Alphabet)
+	}
+
+	var buf [8]byte
+	binary.BigEndian.PutUint64(buf[:], v)
+	return hex.AppendEncode(b, buf[:])
+}
+
+// Decodes a value from its encoding. Errors are one of the causes of ParseError, along with the
+// offset of the offending character, or -1.
+func (e Encoding) decode(str string) (v uint64, off int, err error) {
+	if len(str) != e.Len() {
+		return 0, -1, ErrInvalidLength
+	}
+
+	switch e {
+	case Base32:
+		for i := range len(str) {
+			d := base32Decode[str[i]]
+
+			if d == 0xff {
+				return 0, i, ErrInvalidCharacter
+			}
+
+			// The first character only holds the top 4 bits
+			if i == 0 && d > 15 {
+				return 0, i, ErrOutOfRange
+			}
+
+			v = v<<5 | uint64(d)
+		}
+
+		return
+
+	case Base58:
+		return decodeBase(str, base58Decode, 58)
+
+	case Base62:
+		return decodeBase(str, base62Decode, 62)
+	}
+
+	for i := range len(str) {
+		d := hexDecode[str[i]]
+
+		if d == 0xff {
+			return 0, i, ErrInvalidCharacter
+		}
+
+		v = v<<4 | uint64(d)
+	}
+
+	return v, -1, nil
+}
+
+// Name of the encoding in ParseError, with or without a check character.
+func (e Encoding) format(checked bool) string {
+	if checked {
+		return "checked " + e.String()
+	}
+
+	return e.String()
+}
+
+// Appends v in the base of the alphabet, left-padded to 11 characters.
+func appendBase(b []byte, v uint64, alphabet string) []byte {
+	var buf [11]byte
+	base := uint64(len(alphabet))
+
+	for i := len(buf) - 1; i >= 0; i-- {
+		buf[i] = alphabet[v%base]
+		v /= base
+	}
+
+	return append(b, buf[:]...)
+}
+
+func decodeBase(str string, table *[256]byte, base uint64) (v uint64, off int, err error) {
+	for i := range len(str) {
+		d := table[str[i]]
+
+		if d == 0xff {
+			return 0, i, ErrInvalidCharacter
+		}
+
+		hi, lo := bits.Mul64(v, base)
+		lo, carry := bits.Add64(lo, uint64(d), 0)
+
+		if hi != 0 || carry != 0 {
+			return 0, i, ErrOutOfRange
+		}
+
+		v = lo
+	}
+
+	return v, -1, nil
+}
+
+func decodeTable(alphabet string) *[256]byte {
+	var t [256]byte
+
+	for i := range t {
+		t[i] = 0xff
+	}
+
+	for i := range len(alphabet) {
+		t[alphabet[i]] = byte(i)
+	}
+
+	return &t
+}
+
+// Appends the base32 encoding of the ID to b, with the coder set by SetCoder.
+func (id ID) AppendBase32(b []byte) []byte {
+	return getCoder().appendEncoding(b, id, Base32)
+}
+
+// Appends the base58 encoding of the ID to b, with the coder set by SetCoder.
+func (id ID) AppendBase58(b []byte) []byte {
+	return getCoder().appendEncoding(b, id, Base58)
+}
+
+// Appends the base62 encoding of the ID to b, with the coder set by SetCoder.
+func (id ID) AppendBase62(b []byte) []byte {
+	return getCoder().appendEncoding(b, id, Base62)
+}
+
+// Decodes an ID from its 13-character base32 encoding, with the coder set by SetCoder.
+func IDFromBase32(str string) (ID, error) {
+	return getCoder().decodeEncoding(str, Base32)
+}
+
+// Decodes an ID from its 11-character base58 encoding, with the coder set by SetCoder.
+func IDFromBase58(str string) (ID, error) {
+	return getCoder().decodeEncoding(str, Base58)
+}
+
+// Decodes an ID from its 11-character base62 encoding, with the coder set by SetCoder.
+func IDFromBase62(str string) (ID, error) {
+	return getCoder().decodeEncoding(str, Base62)
+}
@@ -0,0 +1,181 @@
+package hexid
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"math/rand/v2"
+	"strings"
+	"testing"
+)
+
+func ExampleID_AppendBase58() {
+	id := ID(0x1234567890abcdef)
+
+	fmt.Println(string(id.AppendBase32(nil)))
+	fmt.Println(string(id.AppendBase58(nil)))
+	fmt.Println(string(id.AppendBase62(nil)))
+
+	// Output:
+	// BXTCR4ZX258P1
+	// Yw6ENnNYjbW
+	// GODnpSJkMan
+}
+
+func TestEncodings(t *testing.T) {
+	values := []uint64{0, 1, math.MaxUint64, math.MaxUint64 - 1}
+
+	for range 1000 {
+		values = append(values, rand.Uint64())
+	}
+
+	for _, e := range []Encoding{Hex, Base32, Base58, Base62} {
+		var prev string
+
+		for i, v := range values {
+			str := string(e.append(nil, v))
+
+			if len(str) != e.Len() {
+				t.Fatalf("expected %d characters of %s, got %q", e.Len(), e, str)
+			}
+
+			if got, _, err := e.decode(str); err != nil || got != v {
+				t.Fatalf("expected %x from %s %q, got %x (%v)", v, e, str, got, err)
+			}
+
+			// Strings must compare like the values
+			if i > 0 && (str < prev) != (v < values[i-1]) {
+				t.Fatalf("expected %s %q and %q to compare like %x and %x", e, str, prev, v, values[i-1])
+			}
+
+			prev = str
+		}
+	}
+}
+
+func TestEncodingsInvalid(t *testing.T) {
+	invalid := map[Encoding][]string{
+		Hex:    {"", "0123456789abcdeg", "0123456789abcde"},
+		Base32: {"", "G000000000000", "000000000000U", "00000000000000"},
+		Base58: {"", "00000000000", "zzzzzzzzzzz", "1111111111l"},
+		Base62: {"", "zzzzzzzzzzz", "000000000-0", "0000000000"},
+	}
+
+	for e, strs := range invalid {
+		for _, str := range strs {
+			if v, _, err := e.decode(str); err == nil {
+				t.Errorf("expected %s %q to be invalid, got %x", e, str, v)
+			}
+		}
+	}
+}
+
+func TestBase32Typos(t *testing.T) {
+	id := Generate()
+	str := string(id.AppendBase32(nil))
+	typo := strings.NewReplacer("0", "o", "1", "L").Replace(strings.ToLower(str))
+
+	if got, err := IDFromBase32(typo); err != nil || got != id {
+		t.Fatalf("expected %d from %q, got %d (%v)", id, typo, got, err)
+	}
+}
+
+func TestIDFromEncodings(t *testing.T) {
+	id := Generate()
+
+	for _, tc := range []struct {
+		str   []byte
+		parse func(string) (ID, error)
+	}{
+		{id.AppendBase32(nil), IDFromBase32},
+		{id.AppendBase58(nil), IDFromBase58},
+		{id.AppendBase62(nil), IDFromBase62},
+	} {
+		if got, err := tc.parse(string(tc.str)); err != nil || got != id {
+			t.Fatalf("expected %d from %q, got %d (%v)", id, tc.str, got, err)
+		}
+	}
+}
+
+func TestCoderWithEncoding(t *testing.T) {
+	c, err := DefaultCoder.WithEncoding(Base58)
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	SetCoder(c)
+	t.Cleanup(func() { SetCoder(nil) })
+
+	id := Generate()
+
+	if got, want := id.String(), string(id.AppendBase58(nil)); got != want {
+		t.Fatalf("expected %s, got %s", want, got)
+	}
+
+	b, _ := json.Marshal(id)
+
+	if want := `"` + id.String() + `"`; string(b) != want {
+		t.Fatalf("expected %s, got %s", want, b)
+	}
+
+	var got ID
+
+	if err := json.Unmarshal(b, &got); err != nil || got != id {
+		t.Fatalf("expected %d, got %d (%v)", id, got, err)
+	}
+
+	if err := got.Scan(id.String()); err != nil || got != id {
+		t.Fatalf("expected %d, got %d (%v)", id, got, err)
+	}
+
+	if _, err := c.SQL(); err == nil {
+		t.Fatal("expected no SQL for base58")
+	}
+
+	if _, err := c.WithEncoding(Encoding(99)); err == nil {
+		t.Fatal("expected an invalid encoding to fail")
+	}
+}
+
+type product struct{}
+
+func (product) Prefix() string     { return "prd" }
+func (product) Encoding() Encoding { return Base62 }
+
+func TestTypedIDEncoding(t *testing.T) {
+	id := GenerateTyped[product]()
+	str := id.String()
+
+	if want := "prd_" + string(id.ID().AppendBase62(nil)); str != want {
+		t.Fatalf("expected %s, got %s", want, str)
+	}
+
+	if got, err := ParseTypedID[product](str); err != nil || got != id {
+		t.Fatalf("expected %s, got %s (%v)", id, got, err)
+	}
+}
+
+func BenchmarkEncoding(b *testing.B) {
+	id := Generate()
+
+	for _, e := range []Encoding{Hex, Base32, Base58, Base62} {
+		b.Run(e.String()+"/Append", func(b *testing.B) {
+			buf := make([]byte, 0, 16)
+			b.ReportAllocs()
+
+			for range b.N {
+				buf = getCoder().appendEncoding(buf[:0], id, e)
+			}
+		})
+
+		b.Run(e.String()+"/Decode", func(b *testing.B) {
+			str := string(getCoder().appendEncoding(nil, id, e))
+			b.ReportAllocs()
+
+			for range b.N {
+				_, _ = getCoder().decodeEncoding(str, e)
+			}
+		})
+	}
+}
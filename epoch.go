@@ -0,0 +1,68 @@
+package hexid
+
+import (
+	"errors"
+	"time"
+)
+
+// ErrBeforeEpoch is returned by TryID when the clock is before the generator's epoch.
+var ErrBeforeEpoch = errors.New("time is before the epoch")
+
+// An Epoch is the point in time, in unix seconds, that the timestamps of IDs count from. A custom
+// epoch extends the range of IDs beyond the year 2106, but must then be shared by everything that
+// reads the timestamps of IDs. The default is UnixEpoch, which is also what the SQL functions use.
+type Epoch int64
+
+// The unix epoch (1970-01-01 00:00:00 UTC).
+const UnixEpoch Epoch = 0
+
+// Create an epoch at the provided time, truncated to whole seconds.
+func NewEpoch(t time.Time) Epoch {
+	return Epoch(t.Unix())
+}
+
+// Unix returns the Unix timestamp in seconds of an ID generated with this epoch.
+func (e Epoch) Unix(id ID) int64 {
+	return int64(id.Unix()) + int64(e)
+}
+
+// Time reconstructs the approximate creation time of an ID generated with this epoch.
+func (e Epoch) Time(id ID) time.Time {
+	if id.Hashed() {
+		return time.Time{}
+	}
+
+	return time.Unix(e.Unix(id), int64(id.Millis())*1_000_000)
+}
+
+// Reassambles an ID generated with this epoch from its Unix timestamp and `(ID).Entropy()`.
+func (e Epoch) IDFromEntropy(unix int64, entropy uint32) ID {
+	return IDFromEntropy(uint32(unix-int64(e)), entropy)
+}
+
+func (e Epoch) nanos() int64 {
+	return int64(e) * 1e9
+}
+
+// Shifts ts so that its unix timestamp counts from the epoch.
+func (e Epoch) shift(ts time.Time) time.Time {
+	return ts.Add(-time.Duration(e.nanos()))
+}
+
+// Epoch that the timestamps of generated IDs count from. Defaults to UnixEpoch. Fails if it
+// conflicts with the epoch of the layout set by WithLayout.
+func WithEpoch(e Epoch) Option {
+	return func(c *config) error {
+		if c.layout.NodeBits != 0 && c.layout.Epoch != UnixEpoch && c.layout.Epoch != e {
+			return errors.New("epoch conflicts with the epoch of the layout set by WithLayout")
+		}
+
+		c.layout.Epoch = e
+		return nil
+	}
+}
+
+// Epoch that the timestamps of the generator's IDs count from.
+func (c *config) Epoch() Epoch {
+	return c.layout.Epoch
+}
@@ -0,0 +1,78 @@
+package hexid
+
+import (
+	"fmt"
+	"testing"
+	"time"
+)
+
+func ExampleEpoch() {
+	epoch := NewEpoch(time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC))
+	ts := time.Date(2150, 6, 1, 12, 0, 0, 0, time.UTC)
+
+	g, _ := New(WithEpoch(epoch), WithClock(NewManualClock(ts)))
+	id := g.ID()
+
+	fmt.Println(epoch.Time(id).UTC())
+
+	// Output: 2150-06-01 12:00:00 +0000 UTC
+}
+
+func TestEpoch(t *testing.T) {
+	epoch := NewEpoch(time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC))
+	ts := time.Date(2025, 1, 1, 0, 0, 0, 123_000_000, time.UTC)
+
+	g, err := New(WithEpoch(epoch), WithClock(NewManualClock(ts)))
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, id := range []ID{g.ID(), g.IDFromTime(ts)} {
+		if got, want := int64(id.Unix()), ts.Unix()-int64(epoch); got != want {
+			t.Fatalf("expected %d seconds since the epoch, got %d", want, got)
+		}
+
+		if got := epoch.Unix(id); got != ts.Unix() {
+			t.Fatalf("expected unix %d, got %d", ts.Unix(), got)
+		}
+
+		if got := epoch.Time(id); !got.Equal(ts) {
+			t.Fatalf("expected %v, got %v", ts, got)
+		}
+
+		if got := epoch.IDFromEntropy(epoch.Unix(id), id.Entropy()); got != id {
+			t.Fatalf("expected %s, got %s", id, got)
+		}
+	}
+
+	if got := g.Epoch(); got != epoch {
+		t.Fatalf("expected epoch %d, got %d", epoch, got)
+	}
+
+	if got := UnixEpoch.Time(HashedID("foo")); !got.IsZero() {
+		t.Fatalf("expected zero time for hashed ID, got %v", got)
+	}
+}
+
+func TestEpochBeforeClock(t *testing.T) {
+	epoch := NewEpoch(time.Date(2030, 1, 1, 0, 0, 0, 0, time.UTC))
+	ts := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	g, _ := New(WithEpoch(epoch), WithClock(NewManualClock(ts)))
+
+	if _, err := g.TryID(); err != ErrBeforeEpoch {
+		t.Fatalf("expected ErrBeforeEpoch, got %v", err)
+	}
+}
+
+func TestUnixEpoch(t *testing.T) {
+	id := IDFromTime(time.Unix(1730000000, 123_000_000))
+
+	if !UnixEpoch.Time(id).Equal(id.Time()) {
+		t.Fatalf("expected %v, got %v", id.Time(), UnixEpoch.Time(id))
+	}
+
+	if UnixEpoch.IDFromEntropy(int64(id.Unix()), id.Entropy()) != IDFromEntropy(id.Unix(), id.Entropy()) {
+		t.Fatal("expected the unix epoch to reassemble the same ID")
+	}
+}
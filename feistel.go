@@ -0,0 +1,110 @@
+package hexid
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/binary"
+	"errors"
+	"math/bits"
+)
+
+// Number of rounds of the Feistel network.
+const feistelRounds = 8
+
+// A balanced Feistel network over the 64 bits of an ID, with two 32-bit halves and SipHash-2-4 as
+// the round function. Unlike multiplicative scrambling, it's a keyed block cipher, so neither the
+// encoding of an ID nor those of adjacent IDs reveal anything about each other.
+type feistel struct {
+	k0, k1 uint64
+}
+
+// Create a coder that encrypts IDs with a Feistel network keyed by a secret key, which must be
+// shared by everything that encodes or decodes IDs. The encoding has the same format as that of
+// other coders, but has no SQL counterpart, and is almost an order of magnitude slower to compute.
+func NewFeistelCoder(key []byte) (*Coder, error) {
+	if len(key) == 0 {
+		return nil, errors.New("key must not be empty")
+	}
+
+	mac := hmac.New(sha256.New, key)
+	mac.Write([]byte("hexid feistel"))
+	sum := mac.Sum(nil)
+
+	return &Coder{
+		feistel: &feistel{
+			k0: binary.LittleEndian.Uint64(sum[0:8]),
+			k1: binary.LittleEndian.Uint64(sum[8:16]),
+		},
+	}, nil
+}
+
+func (f *feistel) encrypt(v uint64) uint64 {
+	l, r := uint32(v>>32), uint32(v)
+
+	for i := range feistelRounds {
+		l, r = r, l^f.round(i, r)
+	}
+
+	return uint64(l)<<32 | uint64(r)
+}
+
+func (f *feistel) decrypt(v uint64) uint64 {
+	l, r := uint32(v>>32), uint32(v)
+
+	for i := feistelRounds - 1; i >= 0; i-- {
+		l, r = r^f.round(i, l), l
+	}
+
+	return uint64(l)<<32 | uint64(r)
+}
+
+func (f *feistel) round(i int, r uint32) uint32 {
+	return uint32(sipHash(f.k0, f.k1, uint64(i)<<32|uint64(r)))
+}
+
+// SipHash-2-4 of a single 8-byte little-endian message.
+func sipHash(k0, k1, m uint64) uint64 {
+	v0 := k0 ^ 0x736f6d6570736575
+	v1 := k1 ^ 0x646f72616e646f6d
+	v2 := k0 ^ 0x6c7967656e657261
+	v3 := k1 ^ 0x7465646279746573
+
+	// Message block
+	v3 ^= m
+	v0, v1, v2, v3 = sipRound(v0, v1, v2, v3)
+	v0, v1, v2, v3 = sipRound(v0, v1, v2, v3)
+	v0 ^= m
+
+	// Final block, which holds nothing but the message length of 8 bytes
+	const b = 8 << 56
+	v3 ^= b
+	v0, v1, v2, v3 = sipRound(v0, v1, v2, v3)
+	v0, v1, v2, v3 = sipRound(v0, v1, v2, v3)
+	v0 ^= b
+
+	v2 ^= 0xff
+	v0, v1, v2, v3 = sipRound(v0, v1, v2, v3)
+	v0, v1, v2, v3 = sipRound(v0, v1, v2, v3)
+	v0, v1, v2, v3 = sipRound(v0, v1, v2, v3)
+	v0, v1, v2, v3 = sipRound(v0, v1, v2, v3)
+
+	return v0 ^ v1 ^ v2 ^ v3
+}
+
+func sipRound(v0, v1, v2, v3 uint64) (uint64, uint64, uint64, uint64) {
+	v0 += v1
+	v1 = bits.RotateLeft64(v1, 13)
+	v1 ^= v0
+	v0 = bits.RotateLeft64(v0, 32)
+	v2 += v3
+	v3 = bits.RotateLeft64(v3, 16)
+	v3 ^= v2
+	v0 += v3
+	v3 = bits.RotateLeft64(v3, 21)
+	v3 ^= v0
+	v2 += v1
+	v1 = bits.RotateLeft64(v1, 17)
+	v1 ^= v2
+	v2 = bits.RotateLeft64(v2, 32)
+	return v0, v1, v2, v3
+}
@@ -0,0 +1,101 @@
+package hexid
+
+import (
+	"math/bits"
+	"testing"
+)
+
+func TestSipHash(t *testing.T) {
+	// Reference vector of SipHash-2-4 with key 00..0f and message 00..07
+	if got := sipHash(0x0706050403020100, 0x0f0e0d0c0b0a0908, 0x0706050403020100); got != 0x93f5f5799a932462 {
+		t.Fatalf("expected 93f5f5799a932462, got %x", got)
+	}
+}
+
+func TestFeistelCoderVectors(t *testing.T) {
+	c, err := NewFeistelCoder([]byte("hexid test key"))
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	vectors := []struct {
+		id  ID
+		enc string
+	}{
+		{0, "cfdeefa192ad515b"},
+		{1, "0e88e7438869bc4e"},
+		{2, "357023fcde8e1839"},
+		{0x1234567890abcdef, "d59a1dd3fc9fba55"},
+		{0x7fffffffffffffff, "7e9b9224fba727d2"},
+	}
+
+	for _, v := range vectors {
+		if got := c.Encode(v.id); got != v.enc {
+			t.Errorf("expected %d to encode as %s, got %s", v.id, v.enc, got)
+		}
+
+		if got, err := c.Decode(v.enc); err != nil || got != v.id {
+			t.Errorf("expected %s to decode as %d, got %d (%v)", v.enc, v.id, got, err)
+		}
+	}
+
+	if _, err := c.SQL(); err == nil {
+		t.Error("expected no SQL for a feistel coder")
+	}
+}
+
+func TestFeistelCoderAvalanche(t *testing.T) {
+	c, _ := NewFeistelCoder([]byte("hexid test key"))
+	g, _ := New()
+	prev := c.feistel.encrypt(uint64(g.ID()))
+	total := 0
+
+	for range 1000 {
+		id := g.ID()
+		enc := c.feistel.encrypt(uint64(id))
+
+		if c.feistel.decrypt(enc) != uint64(id) {
+			t.Fatalf("expected %d to round-trip", id)
+		}
+
+		total += bits.OnesCount64(enc ^ prev)
+		prev = enc
+	}
+
+	// Adjacent IDs should differ in about half of the bits of their encodings
+	if avg := float64(total) / 1000; avg < 30 || avg > 34 {
+		t.Fatalf("expected about 32 differing bits, got %.1f", avg)
+	}
+}
+
+func BenchmarkCoder(b *testing.B) {
+	keyed, _ := NewCoder([]byte("secret"))
+	feistel, _ := NewFeistelCoder([]byte("secret"))
+	id := Generate()
+
+	for _, bb := range []struct {
+		name  string
+		coder *Coder
+	}{
+		{"Default", DefaultCoder},
+		{"Keyed", keyed},
+		{"Feistel", feistel},
+	} {
+		b.Run(bb.name+"/Encode", func(b *testing.B) {
+			var buf []byte
+
+			for range b.N {
+				buf = bb.coder.AppendEncode(buf[:0], id)
+			}
+		})
+
+		b.Run(bb.name+"/Decode", func(b *testing.B) {
+			str := bb.coder.Encode(id)
+
+			for range b.N {
+				_, _ = bb.coder.Decode(str)
+			}
+		})
+	}
+}
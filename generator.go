@@ -1,78 +1,293 @@
 package hexid
 
 import (
-	"fmt"
-	"math/rand"
 	"sync/atomic"
 	"time"
 )
 
-// Non-thread-safe ID generator. Can generate up to 2^15 (32,768) locally unique IDs per millisecond per node.
+// Non-thread-safe ID generator. Can generate up to 2^15 (32,768) locally unique IDs per millisecond
+// per node with DefaultLayout.
 type Generator struct {
-	seq  uint32
-	node uint8
+	_       noCopy
+	state   state
+	monitor clockMonitor
+	seq     uint32
+	config
 }
 
 // Create an ID generator. The generator is NOT thread-safe.
 func NewGenerator(node ...uint8) (g Generator, err error) {
-	var n uint8 = 1
+	cfg, err := newConfig(withNodes(node))
 
-	if len(node) > 0 {
-		n = node[0]
-
-		if n < 1 || n > 63 {
-			err = fmt.Errorf("node must be between 1 and 63")
-			return
-		}
+	if err != nil {
+		return
 	}
 
 	return Generator{
-		seq:  rand.Uint32(),
-		node: n,
+		seq:    cfg.seq,
+		config: cfg,
 	}, nil
 }
 
-func (g *Generator) ID() (id ID) {
-	id = newID(time.Now(), g.node, uint16(g.seq))
-	g.seq++
-	return
+// Set what happens when the sequence of the current millisecond is exhausted. Must be set
+// before the generator is used.
+func (g *Generator) SetOverflowPolicy(p OverflowPolicy) error {
+	return WithOverflowPolicy(p)(&g.config)
+}
+
+// Set what happens when the clock moves backwards. Must be set before the generator is used.
+func (g *Generator) SetRegressionPolicy(p RegressionPolicy) error {
+	return WithRegressionPolicy(p)(&g.config)
+}
+
+// Make every ID greater than the previous one. Must be set before the generator is used. See
+// WithMonotonic.
+func (g *Generator) SetMonotonic() {
+	WithMonotonic()(&g.config)
+}
+
+// Keep stats of the generator. Must be set before the generator is used. See WithStats.
+func (g *Generator) SetStats() {
+	WithStats()(&g.config)
+}
+
+// Set the clock that the generator reads the time from. Must be set before the generator is used.
+func (g *Generator) SetClock(c Clock) {
+	g.clock = c
+}
+
+// Number of times the generator has observed the clock moving backwards.
+func (g *Generator) Regressions() uint64 {
+	return g.monitor.count()
+}
+
+// Snapshot of the generator's stats. See WithStats.
+func (g *Generator) Stats() Stats {
+	return g.stats.snapshot(&g.monitor, g.limit())
+}
+
+// Generates the next ID based on current time. Panics if TryID would return an error.
+func (g *Generator) ID() ID {
+	id, err := g.TryID()
+
+	if err != nil {
+		panic(err)
+	}
+
+	return id
+}
+
+// Generates the next ID based on current time. Fails with ErrSequenceExhausted or
+// ErrClockMovedBackwards when the generator's policies say so, and with ErrLeaseLost when
+// the generator's node lease isn't valid.
+func (g *Generator) TryID() (ID, error) {
+	if g.lease != nil && !g.lease.Valid() {
+		return 0, ErrLeaseLost
+	}
+
+	for {
+		s, wait, err := g.advance(g.state, &g.monitor, 1, g.limit())
+
+		if err != nil {
+			return 0, err
+		}
+
+		if wait > 0 {
+			g.sleep(wait)
+			continue
+		}
+
+		g.state = s
+		g.stats.issue(s, 1, g.limit())
+		return g.Layout().fromMilli(s.ms(), g.node, g.base+s.n()-1), nil
+	}
 }
 
+// Generates an ID based on provided timestamp. IDs generated this way are not tracked against
+// the sequence limit, and it's up to the caller to not exceed the layout's SeqLimit per millisecond.
 func (g *Generator) IDFromTime(ts time.Time) (id ID) {
-	id = newID(ts, g.node, uint16(g.seq))
+	l := g.Layout()
+	id = l.fromTime(l.Epoch.shift(ts), g.node, g.seq)
 	g.seq++
 	return
 }
 
-// Thread-safe ID generator. Can generate up to 2^15 (32,768) locally unique IDs per millisecond per node.
+// Thread-safe ID generator. Can generate up to 2^15 (32,768) locally unique IDs per millisecond per
+// node with DefaultLayout.
 type AtomicGenerator struct {
-	seq  uint32
-	node uint8
+	_       noCopy
+	state   uint64       // Must be 64-bit aligned for atomic access
+	monitor clockMonitor // Must be 64-bit aligned for atomic access
+	seq     uint32
+	config
+}
+
+// Create a thread-safe ID generator with the provided options. Any option that isn't provided
+// falls back to its default.
+func New(opts ...Option) (*AtomicGenerator, error) {
+	cfg, err := newConfig(opts...)
+
+	if err != nil {
+		return nil, err
+	}
+
+	g := &AtomicGenerator{
+		seq:    cfg.seq - 1,
+		config: cfg,
+	}
+
+	if err = g.restore(&g.monitor, &g.state); err != nil {
+		return nil, err
+	}
+
+	return g, nil
 }
 
 // Create an atomic ID generator. The generator is thread-safe.
 func NewAtomicGenerator(node ...uint8) (g AtomicGenerator, err error) {
-	var n uint8 = 1
-
-	if len(node) > 0 {
-		n = node[0]
+	cfg, err := newConfig(withNodes(node))
 
-		if n < 1 || n > 63 {
-			err = fmt.Errorf("node must be between 1 and 63")
-			return
-		}
+	if err != nil {
+		return
 	}
 
 	return AtomicGenerator{
-		seq:  rand.Uint32(),
-		node: n,
+		seq:    cfg.seq - 1,
+		config: cfg,
 	}, nil
 }
 
+// Set what happens when the sequence of the current millisecond is exhausted. Must be set
+// before the generator is used.
+func (g *AtomicGenerator) SetOverflowPolicy(p OverflowPolicy) error {
+	return WithOverflowPolicy(p)(&g.config)
+}
+
+// Set what happens when the clock moves backwards. Must be set before the generator is used.
+func (g *AtomicGenerator) SetRegressionPolicy(p RegressionPolicy) error {
+	return WithRegressionPolicy(p)(&g.config)
+}
+
+// Make every ID greater than the previous one. Must be set before the generator is used. See
+// WithMonotonic.
+func (g *AtomicGenerator) SetMonotonic() {
+	WithMonotonic()(&g.config)
+}
+
+// Keep stats of the generator. Must be set before the generator is used. See WithStats.
+func (g *AtomicGenerator) SetStats() {
+	WithStats()(&g.config)
+}
+
+// Set the clock that the generator reads the time from. Must be set before the generator is used.
+func (g *AtomicGenerator) SetClock(c Clock) {
+	g.clock = c
+}
+
+// Number of times the generator has observed the clock moving backwards.
+func (g *AtomicGenerator) Regressions() uint64 {
+	return g.monitor.count()
+}
+
+// Snapshot of the generator's stats. See WithStats.
+func (g *AtomicGenerator) Stats() Stats {
+	return g.stats.snapshot(&g.monitor, g.limit())
+}
+
+// Atomically generates the next ID based on current time. Panics if TryID would return an error.
 func (g *AtomicGenerator) ID() ID {
-	return newID(time.Now(), g.node, uint16(atomic.AddUint32(&g.seq, 1)))
+	id, err := g.TryID()
+
+	if err != nil {
+		panic(err)
+	}
+
+	return id
+}
+
+// Atomically generates the next ID based on current time. Fails with ErrSequenceExhausted or
+// ErrClockMovedBackwards when the generator's policies say so, and with ErrLeaseLost when
+// the generator's node lease isn't valid.
+func (g *AtomicGenerator) TryID() (ID, error) {
+	if g.lease != nil && !g.lease.Valid() {
+		return 0, ErrLeaseLost
+	}
+
+	for {
+		cur := state(atomic.LoadUint64(&g.state))
+		s, wait, err := g.advance(cur, &g.monitor, 1, g.limit())
+
+		if err != nil {
+			return 0, err
+		}
+
+		if wait > 0 {
+			g.sleep(wait)
+			continue
+		}
+
+		if atomic.CompareAndSwapUint64(&g.state, uint64(cur), uint64(s)) {
+			g.stats.issue(s, 1, g.limit())
+			return g.Layout().fromMilli(s.ms(), g.node, g.base+s.n()-1), nil
+		}
+	}
 }
 
+// Atomically generates an ID based on provided timestamp. IDs generated this way are not tracked
+// against the sequence limit, and it's up to the caller to not exceed the layout's SeqLimit per millisecond.
 func (g *AtomicGenerator) IDFromTime(ts time.Time) ID {
-	return newID(ts, g.node, uint16(atomic.AddUint32(&g.seq, 1)))
+	l := g.Layout()
+	return l.fromTime(l.Epoch.shift(ts), g.node, atomic.AddUint32(&g.seq, 1))
+}
+
+// Continue where prev left off, so that no millisecond is reused by the same node.
+func (g *AtomicGenerator) resume(prev *AtomicGenerator) {
+	if prev == nil || prev.node != g.node {
+		return
+	}
+
+	// Milliseconds are counted from each generator's epoch
+	offset := (int64(prev.Epoch()) - int64(g.Epoch())) * 1000
+	s := state(atomic.LoadUint64(&prev.state))
+	cur := state(atomic.LoadUint64(&g.state))
+
+	// The generator might already be further ahead, e.g. when restored from a StateStore
+	if ms := s.ms() + offset; ms > cur.ms() {
+		atomic.StoreUint64(&g.state, uint64(newState(ms, g.limit())))
+	}
+
+	if wall := atomic.LoadInt64(&prev.monitor.wall) + offset; wall > atomic.LoadInt64(&g.monitor.wall) {
+		atomic.StoreInt64(&g.monitor.wall, wall)
+	}
+}
+
+// advance reads the clock, applies the policies, and returns the state after issuing k more IDs
+// with limit IDs per millisecond.
+func (c *config) advance(cur state, m *clockMonitor, k, limit uint32) (state, time.Duration, error) {
+	now, behind, wait, err := m.read(c)
+
+	if err != nil || wait > 0 {
+		return cur, wait, err
+	}
+
+	overflow := c.overflow
+
+	// Waiting for the next millisecond is pointless while the clock is behind
+	if behind && overflow == OverflowWait {
+		overflow = OverflowBorrow
+	}
+
+	s, wait, err := cur.next(now, k, limit, overflow)
+
+	if wait > 0 || err == ErrSequenceExhausted {
+		c.stats.exhaust()
+	}
+
+	if err == nil && wait == 0 && c.persist != nil {
+		if err = c.persist.reach(s.ms(), c.Epoch()); err != nil {
+			return cur, 0, err
+		}
+	}
+
+	return s, wait, err
 }
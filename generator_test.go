@@ -1,7 +1,9 @@
 package hexid
 
 import (
+	"errors"
 	"fmt"
+	"sync"
 	"testing"
 	"time"
 )
@@ -116,3 +118,202 @@ func TestGeneratorWrapAroundDoesNotDuplicate(t *testing.T) {
 		t.Fatalf("IDs are identical despite wrap-around: id1=%v, id2=%v", id1, id2)
 	}
 }
+
+func TestGeneratorOverflowPolicy(t *testing.T) {
+	future := time.Now().Add(time.Hour).UnixMilli()
+
+	t.Run("Borrow", func(t *testing.T) {
+		g, _ := NewGenerator()
+
+		if err := g.SetOverflowPolicy(OverflowBorrow); err != nil {
+			t.Fatal(err)
+		}
+
+		g.state = newState(future, seqLimit)
+		id, err := g.TryID()
+
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if got, want := id.Time().UnixMilli(), future+1; got != want {
+			t.Fatalf("expected borrowed millisecond %d, got %d", want, got)
+		}
+	})
+
+	t.Run("Error", func(t *testing.T) {
+		g, _ := NewAtomicGenerator()
+
+		if err := g.SetOverflowPolicy(OverflowError); err != nil {
+			t.Fatal(err)
+		}
+
+		g.state = uint64(newState(future, seqLimit))
+
+		if _, err := g.TryID(); err != ErrSequenceExhausted {
+			t.Fatalf("expected ErrSequenceExhausted, got %v", err)
+		}
+	})
+
+	t.Run("Wait", func(t *testing.T) {
+		g, _ := NewGenerator()
+		ms := time.Now().UnixMilli()
+		g.state = newState(ms, seqLimit)
+
+		if id := g.ID(); id.Time().UnixMilli() <= ms {
+			t.Fatalf("expected an ID after millisecond %d, got %d", ms, id.Time().UnixMilli())
+		}
+	})
+
+	t.Run("Invalid", func(t *testing.T) {
+		g, _ := NewGenerator()
+
+		if err := g.SetOverflowPolicy(OverflowPolicy(99)); err == nil {
+			t.Fatal("expected an error")
+		}
+	})
+}
+
+func TestAtomicGeneratorNoDuplicates(t *testing.T) {
+	const (
+		workers   = 8
+		perWorker = 20_000
+	)
+
+	g, _ := NewAtomicGenerator()
+	ids := make([][]ID, workers)
+	var wg sync.WaitGroup
+
+	for i := range ids {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			ids[i] = make([]ID, perWorker)
+
+			for j := range ids[i] {
+				ids[i][j] = g.ID()
+			}
+		}()
+	}
+
+	wg.Wait()
+	seen := make(map[ID]struct{}, workers*perWorker)
+
+	for _, list := range ids {
+		for _, id := range list {
+			if _, ok := seen[id]; ok {
+				t.Fatalf("duplicate ID %s", id)
+			}
+
+			seen[id] = struct{}{}
+		}
+	}
+}
+
+func TestMonotonic(t *testing.T) {
+	clock := NewManualClock(time.Now())
+
+	t.Run("Generator", func(t *testing.T) {
+		g, _ := NewGenerator()
+		g.SetClock(clock)
+		g.SetMonotonic()
+		prev := g.ID()
+
+		for i := range 3 * seqLimit {
+			if i%1000 == 0 {
+				clock.Advance(-time.Millisecond)
+			}
+
+			if id := g.ID(); id <= prev {
+				t.Fatalf("expected %d to be greater than %d", id, prev)
+			} else {
+				prev = id
+			}
+		}
+	})
+
+	t.Run("AtomicGenerator", func(t *testing.T) {
+		const workers = 8
+
+		g, _ := New(WithMonotonic(), WithClock(clock), WithOverflowPolicy(OverflowBorrow))
+		var wg sync.WaitGroup
+
+		for range workers {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				prev := g.ID()
+
+				for range seqLimit {
+					if id := g.ID(); id <= prev {
+						t.Errorf("expected %d to be greater than %d", id, prev)
+						return
+					} else {
+						prev = id
+					}
+				}
+			}()
+		}
+
+		wg.Wait()
+	})
+
+	t.Run("Sharded", func(t *testing.T) {
+		if _, err := NewShardedGenerator(4, WithMonotonic()); err == nil {
+			t.Fatal("expected an error")
+		}
+	})
+}
+
+func TestGeneratorRegressionPolicy(t *testing.T) {
+	ahead := time.Now().Add(time.Hour).UnixMilli()
+
+	t.Run("Logical", func(t *testing.T) {
+		g, _ := NewGenerator()
+		g.monitor.wall = ahead
+		g.monitor.last = ahead
+
+		if got := g.ID().Time().UnixMilli(); got != ahead {
+			t.Fatalf("expected millisecond %d, got %d", ahead, got)
+		}
+
+		if got := g.Regressions(); got != 1 {
+			t.Fatalf("expected 1 regression, got %d", got)
+		}
+	})
+
+	t.Run("Wait", func(t *testing.T) {
+		g, _ := NewAtomicGenerator()
+		ms := time.Now().Add(5 * time.Millisecond).UnixMilli()
+		g.monitor.wall = ms
+
+		if err := g.SetRegressionPolicy(RegressionWait); err != nil {
+			t.Fatal(err)
+		}
+
+		if got := g.ID().Time().UnixMilli(); got < ms {
+			t.Fatalf("expected millisecond >= %d, got %d", ms, got)
+		}
+	})
+
+	t.Run("Error", func(t *testing.T) {
+		g, _ := NewAtomicGenerator()
+		g.monitor.wall = ahead
+
+		if err := g.SetRegressionPolicy(RegressionError); err != nil {
+			t.Fatal(err)
+		}
+
+		if _, err := g.TryID(); !errors.Is(err, ErrClockMovedBackwards) {
+			t.Fatalf("expected ErrClockMovedBackwards, got %v", err)
+		}
+	})
+
+	t.Run("Invalid", func(t *testing.T) {
+		g, _ := NewGenerator()
+
+		if err := g.SetRegressionPolicy(RegressionPolicy(99)); err == nil {
+			t.Fatal("expected an error")
+		}
+	})
+}
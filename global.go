@@ -1,19 +1,115 @@
 package hexid
 
-import "time"
+import (
+	"fmt"
+	"os"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+)
 
-var gen AtomicGenerator
+var (
+	gen    atomic.Pointer[AtomicGenerator]
+	genErr atomic.Pointer[error]
+	genMu  sync.Mutex
+)
 
 func init() {
-	gen, _ = NewAtomicGenerator()
+	g, err := newGlobal()
+
+	// Panicking here would crash every program that imports the package, before it can handle the
+	// error. Instead, the error is kept for GlobalErr, and Generate panics with it until the global
+	// generator is reconfigured.
+	if err != nil {
+		err = fmt.Errorf("hexid: %w", err)
+		genErr.Store(&err)
+		g, _ = New()
+	}
+
+	gen.Store(g)
+}
+
+// Create the global generator, with the node from NodeEnv if it's set and not empty.
+func newGlobal() (*AtomicGenerator, error) {
+	var opts []Option
+
+	// An explicitly set node must never be silently ignored
+	if v, ok := os.LookupEnv(NodeEnv); ok && strings.TrimSpace(v) != "" {
+		opts = append(opts, WithNodeResolver(NodeFromEnv(NodeEnv)))
+	}
+
+	return New(opts...)
+}
+
+// Error from creating the global generator at startup, e.g. an invalid node in the HEXID_NODE
+// environment variable. Until the global generator is successfully reconfigured, Generate,
+// IDFromTime, Fill and GenerateN panic with it, and TryGenerate returns it.
+func GlobalErr() error {
+	if err := genErr.Load(); err != nil {
+		return *err
+	}
+
+	return nil
+}
+
+// Reconfigure the global generator with the provided options. Options that aren't provided fall
+// back to their defaults. The new generator continues where the previous one left off, but any
+// IDs that are being generated during the call might collide - so this should be called at
+// startup, before generating any IDs.
+func Configure(opts ...Option) error {
+	g, err := New(opts...)
+
+	if err != nil {
+		return err
+	}
+
+	genMu.Lock()
+	defer genMu.Unlock()
+
+	g.resume(gen.Load())
+	gen.Store(g)
+	genErr.Store(nil)
+	return nil
+}
+
+// Reconfigure the global generator with a node ID between 1 and 63. See Configure.
+func SetGlobalNode(node uint16) error {
+	return Configure(WithNode(node))
+}
+
+// Snapshot of the global generator's stats. Only kept when configured with WithStats.
+func GlobalStats() Stats {
+	return gen.Load().Stats()
 }
 
-// Atomically generates the next ID based on current time. Thread-safe.
+// Atomically generates the next ID based on current time. Thread-safe. Panics with GlobalErr if
+// the global generator couldn't be created as configured at startup.
 func Generate() ID {
-	return gen.ID()
+	return mustGlobal().ID()
+}
+
+// Like Generate, but fails with GlobalErr if the global generator couldn't be created as
+// configured at startup, and with the errors of (*AtomicGenerator).TryID.
+func TryGenerate() (ID, error) {
+	if err := GlobalErr(); err != nil {
+		return 0, err
+	}
+
+	return gen.Load().TryID()
 }
 
 // Atomically generates the next ID based on provided timestamp. Thread-safe.
 func IDFromTime(ts time.Time) ID {
-	return gen.IDFromTime(ts)
+	return mustGlobal().IDFromTime(ts)
+}
+
+// Global generator, which must not be used to generate IDs while GlobalErr is set, as it then
+// doesn't have the configured node.
+func mustGlobal() *AtomicGenerator {
+	if err := genErr.Load(); err != nil {
+		panic(*err)
+	}
+
+	return gen.Load()
 }
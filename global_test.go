@@ -0,0 +1,113 @@
+package hexid
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestConfigure(t *testing.T) {
+	prev := gen.Load()
+	t.Cleanup(func() { gen.Store(prev) })
+
+	id1 := Generate()
+
+	if err := SetGlobalNode(prev.node); err != nil {
+		t.Fatal(err)
+	}
+
+	// The new generator must continue in a later millisecond than the previous one
+	if id2 := Generate(); id2.Time().UnixMilli() <= id1.Time().UnixMilli() {
+		t.Fatalf("expected %v to be after %v", id2.Time(), id1.Time())
+	}
+
+	if err := SetGlobalNode(42); err != nil {
+		t.Fatal(err)
+	}
+
+	if id := Generate(); id.Node() != 42 {
+		t.Fatalf("expected node 42, got %d", id.Node())
+	}
+
+	if err := SetGlobalNode(64); err == nil {
+		t.Fatal("expected an error")
+	}
+
+	if id := Generate(); id.Node() != 42 {
+		t.Fatalf("expected a failed configuration to keep node 42, got %d", id.Node())
+	}
+}
+
+func TestGlobalFromEnv(t *testing.T) {
+	for _, tc := range []struct {
+		value string
+		node  uint8
+		fails bool
+	}{
+		{"", 1, false},
+		{" ", 1, false},
+		{"12", 12, false},
+		{"abc", 0, true},
+		{"64", 0, true},
+	} {
+		t.Setenv(NodeEnv, tc.value)
+		g, err := newGlobal()
+
+		if tc.fails {
+			if err == nil {
+				t.Errorf("expected %s=%q to fail", NodeEnv, tc.value)
+			}
+
+			continue
+		}
+
+		if err != nil {
+			t.Errorf("expected %s=%q to succeed, got %v", NodeEnv, tc.value, err)
+		} else if id := g.ID(); id.Node() != tc.node {
+			t.Errorf("expected node %d from %s=%q, got %d", tc.node, NodeEnv, tc.value, id.Node())
+		}
+	}
+}
+
+func TestGlobalErr(t *testing.T) {
+	prev := gen.Load()
+	t.Cleanup(func() {
+		gen.Store(prev)
+		genErr.Store(nil)
+	})
+
+	err := errors.New("hexid: invalid node")
+	genErr.Store(&err)
+
+	if _, got := TryGenerate(); got != err || GlobalErr() != err {
+		t.Fatalf("expected %v, got %v", err, got)
+	}
+
+	// IDs must not be generated on the fallback node
+	for name, fn := range map[string]func(){
+		"Generate":   func() { Generate() },
+		"IDFromTime": func() { IDFromTime(time.Now()) },
+		"Fill":       func() { Fill(make([]ID, 1)) },
+		"GenerateN":  func() { GenerateN(nil, 1) },
+	} {
+		func() {
+			defer func() {
+				if got := recover(); got != err {
+					t.Errorf("expected %s to panic with %v, got %v", name, err, got)
+				}
+			}()
+
+			fn()
+		}()
+	}
+
+	if err := SetGlobalNode(prev.node); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := TryGenerate(); err != nil || GlobalErr() != nil {
+		t.Fatalf("expected a successful Configure to clear the error, got %v", err)
+	}
+
+	Generate()
+}
@@ -24,66 +24,34 @@ This structure guarantees:
 
 type ID uint64
 
-// newID generates a new 63-bit ID based on the given timestamp, node ID, and sequence counter.
-func newID(ts time.Time, nodeID uint8, seq uint16) ID {
-	const (
-		msBits   = 10
-		nodeBits = 6
-		seqBits  = 15
-
-		nodeShift = seqBits
-		msShift   = nodeShift + nodeBits
-		secShift  = msShift + msBits
-
-		mask63 = 0x7FFFFFFFFFFFFFFF // ensure top bit = 0
-	)
-
-	secs := uint64(ts.Unix())
-	msecs := uint64(ts.Nanosecond() / 1_000_000)
-
-	id := (secs << secShift) |
-		(msecs << msShift) |
-		(uint64(nodeID) << nodeShift) |
-		(uint64(seq) & ((1 << seqBits) - 1))
-
-	return ID(id & mask63)
-}
-
 // Unix returns the Unix timestamp in seconds.
 func (id ID) Unix() uint32 {
-	return uint32(id >> 31) // shift away ms+node+seq bits
+	return uint32(DefaultLayout.Unix(id))
 }
 
 // Millis returns the millisecond part within the second.
 func (id ID) Millis() uint16 {
-	return uint16((id >> 21) & 0x3FF) // 10 bits
+	return DefaultLayout.Millis(id)
 }
 
 // Node returns the 6-bit node ID.
 func (id ID) Node() uint8 {
-	return uint8((id >> 15) & 0x3F)
+	return uint8(DefaultLayout.Node(id))
 }
 
 // Seq returns the 15-bit sequence number.
 func (id ID) Seq() uint16 {
-	return uint16(id & 0x7FFF)
+	return uint16(DefaultLayout.Seq(id))
 }
 
 // Entropy returns everything after the Unix timestamp seconds (milliseconds + node + sequence)
 func (id ID) Entropy() uint32 {
-	// Extract the lower 31 bits: milliseconds (10) + node (6) + sequence (15)
-	return uint32(id & 0x7FFFFFFF)
+	return uint32(id & (1<<DefaultLayout.secShift() - 1))
 }
 
 // Time reconstructs the approximate creation time of the ID.
 func (id ID) Time() time.Time {
-	if id.Hashed() {
-		return time.Time{}
-	}
-
-	secs := int64(id >> 31)
-	ms := int64((id >> 21) & 0x3FF)
-	return time.Unix(secs, ms*1_000_000)
+	return DefaultLayout.Time(id)
 }
 
 // Uint64 returns the raw numeric value of the ID.
@@ -97,7 +65,12 @@ func (id ID) Int64() int64 {
 }
 
 func (id ID) Hashed() bool {
-	return id.Node() == 0
+	return DefaultLayout.Hashed(id)
+}
+
+// Valid reports whether the ID is plausible in DefaultLayout. See (Layout).Valid.
+func (id ID) Valid() bool {
+	return DefaultLayout.Valid(id)
 }
 
 // IsZero reports whether the ID is zero.
@@ -5,54 +5,48 @@ import (
 	"database/sql/driver"
 	"encoding"
 	"encoding/binary"
-	"encoding/hex"
 	"encoding/json"
-	"errors"
 	"fmt"
 	"strconv"
 
 	"github.com/webmafia/hexid/valuer"
 )
 
+// Multiplier of DefaultCoder, and its multiplicative inverse modulo 2^64.
 const (
 	multiplier    uint64 = 0x6eed0e9da4d94a4f
 	invMultiplier uint64 = 0x2f72b4215a3d8caf
 )
 
 var (
-	_ encoding.TextAppender    = ID(0)
-	_ encoding.BinaryAppender  = ID(0)
-	_ encoding.TextMarshaler   = ID(0)
-	_ encoding.TextUnmarshaler = (*ID)(nil)
-	_ json.Marshaler           = ID(0)
-	_ json.Unmarshaler         = (*ID)(nil)
-	_ sql.Scanner              = (*ID)(nil)
-	_ driver.Valuer            = ID(0)
+	_ encoding.TextAppender      = ID(0)
+	_ encoding.BinaryAppender    = ID(0)
+	_ encoding.BinaryMarshaler   = ID(0)
+	_ encoding.BinaryUnmarshaler = (*ID)(nil)
+	_ encoding.TextMarshaler     = ID(0)
+	_ encoding.TextUnmarshaler   = (*ID)(nil)
+	_ json.Marshaler             = ID(0)
+	_ json.Unmarshaler           = (*ID)(nil)
+	_ sql.Scanner                = (*ID)(nil)
+	_ driver.Valuer              = ID(0)
 )
 
+// Decodes an ID from its text encoding, with the coder set by SetCoder.
 func IDFromString(str string) (id ID, err error) {
-	if len(str) != 16 {
-		return 0, errors.New("invalid ID")
-	}
-
-	var buf [8]byte
-	n, err := hex.Decode(buf[:], s2b(str))
+	return getCoder().Decode(str)
+}
 
-	if err != nil {
-		return
+// Decodes an ID from its raw representation of 8 big-endian bytes, as returned by Bytes.
+func IDFromBinary(b []byte) (ID, error) {
+	if len(b) != 8 {
+		return 0, &ParseError{Input: string(b), Offset: -1, Format: "binary", Err: ErrInvalidLength}
 	}
 
-	if n != 8 {
-		return 0, errors.New("invalid ID")
+	if v := binary.BigEndian.Uint64(b); v>>63 == 0 {
+		return ID(v), nil
 	}
 
-	scrambled := binary.BigEndian.Uint64(buf[:])
-
-	// Multiply by the precomputed multiplicative inverse to recover the original value.
-	// The multiplication is performed modulo 2^64.
-	original := scrambled * invMultiplier
-
-	return ID(original), nil
+	return 0, signBitError(string(b), "binary")
 }
 
 // Returns raw representation of the ID as 8 big-endian bytes.
@@ -68,11 +62,7 @@ func (id ID) String() string {
 
 // AppendBinary implements internal.TextAppender.
 func (id ID) AppendText(b []byte) ([]byte, error) {
-	var buf [8]byte
-	scrambled := uint64(id) * multiplier
-	binary.BigEndian.PutUint64(buf[:], scrambled)
-	b = hex.AppendEncode(b, buf[:])
-	return b, nil
+	return getCoder().AppendEncode(b, id), nil
 }
 
 // AppendBinary implements internal.BinaryAppender.
@@ -80,6 +70,17 @@ func (id ID) AppendBinary(b []byte) ([]byte, error) {
 	return binary.BigEndian.AppendUint64(b, uint64(id)), nil
 }
 
+// MarshalBinary implements encoding.BinaryMarshaler, and is also used by encoding/gob.
+func (id ID) MarshalBinary() ([]byte, error) {
+	return id.AppendBinary(make([]byte, 0, 8))
+}
+
+// UnmarshalBinary implements encoding.BinaryUnmarshaler.
+func (id *ID) UnmarshalBinary(data []byte) (err error) {
+	*id, err = IDFromBinary(data)
+	return
+}
+
 // MarshalJSON implements json.Marshaler.
 func (id ID) MarshalJSON() (b []byte, err error) {
 	if id == 0 {
@@ -98,8 +99,8 @@ func (id ID) MarshalJSON() (b []byte, err error) {
 func (id *ID) UnmarshalJSON(b []byte) (err error) {
 
 	// Parse string ID (with quotes)
-	if len(b) == 18 && b[0] == '"' && b[17] == '"' {
-		*id, err = IDFromString(b2s(b[1:17]))
+	if len(b) >= 2 && b[0] == '"' && b[len(b)-1] == '"' {
+		*id, err = IDFromString(b2s(b[1 : len(b)-1]))
 		return
 	}
 
@@ -110,12 +111,8 @@ func (id *ID) UnmarshalJSON(b []byte) (err error) {
 	}
 
 	// Parse integer (no quote)
-	if v, err := strconv.ParseUint(b2s(b), 10, 64); err == nil {
-		*id = ID(v)
-		return nil
-	}
-
-	return errors.New("invalid ID")
+	*id, err = parseDecimal(b2s(b))
+	return
 }
 
 // MarshalText implements encoding.TextMarshaler.
@@ -132,20 +129,26 @@ func (id *ID) UnmarshalText(text []byte) (err error) {
 	return
 }
 
-// Scan implements sql.Scanner.
+// Scan implements sql.Scanner. Negative integers are rejected, as they can't be IDs.
 func (id *ID) Scan(src any) (err error) {
 	switch v := src.(type) {
 	case int64:
+		if v < 0 {
+			return signBitError(strconv.FormatInt(v, 10), "bigint")
+		}
+
 		*id = ID(v)
 	case uint64:
+		if v>>63 != 0 {
+			return signBitError(strconv.FormatUint(v, 10), "bigint")
+		}
+
 		*id = ID(v)
 	case []byte:
 		if len(v) == 8 {
-			*id = ID(binary.BigEndian.Uint64(v))
-		} else if len(v) == 16 {
-			*id, err = IDFromString(b2s(v))
+			*id, err = IDFromBinary(v)
 		} else {
-			err = fmt.Errorf("cannot scan %T of length %d to %T", v, len(v), id)
+			*id, err = IDFromString(b2s(v))
 		}
 	case string:
 		*id, err = IDFromString(v)
@@ -1,6 +1,9 @@
 package hexid
 
 import (
+	"bytes"
+	"encoding/gob"
+	"errors"
 	"fmt"
 	"testing"
 )
@@ -51,5 +54,83 @@ func ExampleIDFromString() {
 		fmt.Println(err)
 	}
 
-	// Output: invalid ID
+	// Output: invalid ID "3784432400289806371": invalid length, expected hex
+}
+
+func TestBinaryRoundTrip(t *testing.T) {
+	type record struct {
+		ID    ID
+		IDs   []ID
+		Typed TypedID[user]
+	}
+
+	id := Generate()
+	in := record{ID: id, IDs: []ID{id, Generate(), HashedID("foo")}, Typed: GenerateTyped[user]()}
+
+	var buf bytes.Buffer
+
+	if err := gob.NewEncoder(&buf).Encode(in); err != nil {
+		t.Fatal(err)
+	}
+
+	var out record
+
+	if err := gob.NewDecoder(&buf).Decode(&out); err != nil {
+		t.Fatal(err)
+	}
+
+	if out.ID != in.ID || out.Typed != in.Typed || len(out.IDs) != len(in.IDs) {
+		t.Fatalf("expected %v, got %v", in, out)
+	}
+
+	for i := range in.IDs {
+		if out.IDs[i] != in.IDs[i] {
+			t.Fatalf("expected %d at %d, got %d", in.IDs[i], i, out.IDs[i])
+		}
+	}
+
+	b, err := id.MarshalBinary()
+
+	if err != nil || !bytes.Equal(b, id.Bytes()) {
+		t.Fatalf("expected %x, got %x (%v)", id.Bytes(), b, err)
+	}
+
+	var fromBinary, fromText ID
+
+	if err := fromBinary.UnmarshalBinary(b); err != nil {
+		t.Fatal(err)
+	}
+
+	text, _ := id.MarshalText()
+
+	if err := fromText.UnmarshalText(text); err != nil {
+		t.Fatal(err)
+	}
+
+	if fromBinary != id || fromText != id || out.ID != id {
+		t.Fatalf("expected %d from all encodings, got %d (binary), %d (text) and %d (gob)", id, fromBinary, fromText, out.ID)
+	}
+}
+
+func TestIDFromBinary(t *testing.T) {
+	id := Generate()
+	b := id.Bytes()
+
+	if got, err := IDFromBinary(b); err != nil || got != id {
+		t.Fatalf("expected %d, got %d (%v)", id, got, err)
+	}
+
+	for _, b := range [][]byte{nil, b[:7], append(b, 0)} {
+		if _, err := IDFromBinary(b); !errors.Is(err, ErrInvalidLength) {
+			t.Errorf("expected ErrInvalidLength for %x, got %v", b, err)
+		}
+	}
+
+	if _, err := IDFromBinary([]byte{0x80, 0, 0, 0, 0, 0, 0, 1}); !errors.Is(err, ErrSignBit) {
+		t.Errorf("expected ErrSignBit, got %v", err)
+	}
+
+	if allocs := testing.AllocsPerRun(100, func() { _, _ = IDFromBinary(b) }); allocs != 0 {
+		t.Errorf("expected no allocations, got %v", allocs)
+	}
 }
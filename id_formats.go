@@ -0,0 +1,252 @@
+package hexid
+
+import (
+	"database/sql"
+	"database/sql/driver"
+	"encoding"
+	"encoding/json"
+	"errors"
+	"strconv"
+)
+
+// IDs with a fixed JSON and text representation, regardless of the encoding and checksum of the
+// coder set by SetCoder, of which only the scrambling applies. Convert to and from ID to choose
+// the representation per type, e.g. per API:
+//
+//	type Event struct {
+//		ID hexid.NumericID `json:"id"`
+//	}
+//
+// All of them are stored in the database like a plain ID, and decode JSON numbers and null like
+// ID does.
+type (
+	NumericID ID // Decimal, and a JSON number
+	HexID     ID // Scrambled hex without a check character, whatever the coder's encoding
+	Base32ID  ID // Scrambled base32 without a check character, as by AppendBase32 by default
+)
+
+var (
+	_ encoding.TextAppender    = NumericID(0)
+	_ encoding.TextMarshaler   = NumericID(0)
+	_ encoding.TextUnmarshaler = (*NumericID)(nil)
+	_ json.Marshaler           = NumericID(0)
+	_ json.Unmarshaler         = (*NumericID)(nil)
+	_ sql.Scanner              = (*NumericID)(nil)
+	_ driver.Valuer            = NumericID(0)
+
+	_ encoding.TextAppender    = HexID(0)
+	_ encoding.TextMarshaler   = HexID(0)
+	_ encoding.TextUnmarshaler = (*HexID)(nil)
+	_ json.Marshaler           = HexID(0)
+	_ json.Unmarshaler         = (*HexID)(nil)
+	_ sql.Scanner              = (*HexID)(nil)
+	_ driver.Valuer            = HexID(0)
+
+	_ encoding.TextAppender    = Base32ID(0)
+	_ encoding.TextMarshaler   = Base32ID(0)
+	_ encoding.TextUnmarshaler = (*Base32ID)(nil)
+	_ json.Marshaler           = Base32ID(0)
+	_ json.Unmarshaler         = (*Base32ID)(nil)
+	_ sql.Scanner              = (*Base32ID)(nil)
+	_ driver.Valuer            = Base32ID(0)
+)
+
+// Plain ID.
+func (id NumericID) ID() ID {
+	return ID(id)
+}
+
+func (id NumericID) String() string {
+	return strconv.FormatUint(uint64(id), 10)
+}
+
+// AppendText implements encoding.TextAppender.
+func (id NumericID) AppendText(b []byte) ([]byte, error) {
+	return strconv.AppendUint(b, uint64(id), 10), nil
+}
+
+// MarshalText implements encoding.TextMarshaler.
+func (id NumericID) MarshalText() ([]byte, error) {
+	return id.AppendText(make([]byte, 0, 19))
+}
+
+// UnmarshalText implements encoding.TextUnmarshaler. Accepts decimal, and the text encoding of
+// the coder set by SetCoder.
+func (id *NumericID) UnmarshalText(text []byte) error {
+	v, err := parseNumeric(b2s(text))
+	*id = NumericID(v)
+	return err
+}
+
+// MarshalJSON implements json.Marshaler. The zero ID is null.
+func (id NumericID) MarshalJSON() ([]byte, error) {
+	if id == 0 {
+		return []byte{'n', 'u', 'l', 'l'}, nil
+	}
+
+	return id.AppendText(make([]byte, 0, 19))
+}
+
+// UnmarshalJSON implements json.Unmarshaler. Strings can be decimal as well.
+func (id *NumericID) UnmarshalJSON(b []byte) error {
+	v, err := unmarshalJSON(b, parseNumeric)
+	*id = NumericID(v)
+	return err
+}
+
+// Scan implements sql.Scanner.
+func (id *NumericID) Scan(src any) error {
+	return (*ID)(id).Scan(src)
+}
+
+// Value implements driver.Valuer, and stores the ID like a plain ID.
+func (id NumericID) Value() (driver.Value, error) {
+	return ID(id).Value()
+}
+
+// Plain ID.
+func (id HexID) ID() ID {
+	return ID(id)
+}
+
+func (id HexID) String() string {
+	b, _ := id.AppendText(make([]byte, 0, 17))
+	return b2s(b)
+}
+
+// AppendText implements encoding.TextAppender.
+func (id HexID) AppendText(b []byte) ([]byte, error) {
+	return getCoder().appendWith(b, ID(id), Hex, false), nil
+}
+
+// MarshalText implements encoding.TextMarshaler.
+func (id HexID) MarshalText() ([]byte, error) {
+	return id.AppendText(make([]byte, 0, 17))
+}
+
+// UnmarshalText implements encoding.TextUnmarshaler.
+func (id *HexID) UnmarshalText(text []byte) error {
+	v, err := parseHex(b2s(text))
+	*id = HexID(v)
+	return err
+}
+
+// MarshalJSON implements json.Marshaler. The zero ID is null.
+func (id HexID) MarshalJSON() ([]byte, error) {
+	return marshalJSON(ID(id), Hex)
+}
+
+// UnmarshalJSON implements json.Unmarshaler.
+func (id *HexID) UnmarshalJSON(b []byte) error {
+	v, err := unmarshalJSON(b, parseHex)
+	*id = HexID(v)
+	return err
+}
+
+// Scan implements sql.Scanner.
+func (id *HexID) Scan(src any) error {
+	return (*ID)(id).Scan(src)
+}
+
+// Value implements driver.Valuer, and stores the ID like a plain ID.
+func (id HexID) Value() (driver.Value, error) {
+	return ID(id).Value()
+}
+
+// Plain ID.
+func (id Base32ID) ID() ID {
+	return ID(id)
+}
+
+func (id Base32ID) String() string {
+	b, _ := id.AppendText(make([]byte, 0, 14))
+	return b2s(b)
+}
+
+// AppendText implements encoding.TextAppender.
+func (id Base32ID) AppendText(b []byte) ([]byte, error) {
+	return getCoder().appendWith(b, ID(id), Base32, false), nil
+}
+
+// MarshalText implements encoding.TextMarshaler.
+func (id Base32ID) MarshalText() ([]byte, error) {
+	return id.AppendText(make([]byte, 0, 14))
+}
+
+// UnmarshalText implements encoding.TextUnmarshaler.
+func (id *Base32ID) UnmarshalText(text []byte) error {
+	v, err := parseBase32(b2s(text))
+	*id = Base32ID(v)
+	return err
+}
+
+// MarshalJSON implements json.Marshaler. The zero ID is null.
+func (id Base32ID) MarshalJSON() ([]byte, error) {
+	return marshalJSON(ID(id), Base32)
+}
+
+// UnmarshalJSON implements json.Unmarshaler.
+func (id *Base32ID) UnmarshalJSON(b []byte) error {
+	v, err := unmarshalJSON(b, parseBase32)
+	*id = Base32ID(v)
+	return err
+}
+
+// Scan implements sql.Scanner.
+func (id *Base32ID) Scan(src any) error {
+	return (*ID)(id).Scan(src)
+}
+
+// Value implements driver.Valuer, and stores the ID like a plain ID.
+func (id Base32ID) Value() (driver.Value, error) {
+	return ID(id).Value()
+}
+
+// Parses a decimal ID, or else an ID in the text encoding of the coder. Decimal goes first, as it
+// is what NumericID emits, and 16 digits are valid hex as well.
+func parseNumeric(str string) (ID, error) {
+	id, err := parseDecimal(str)
+
+	if err != nil && !errors.Is(err, ErrSignBit) {
+		if v, e := IDFromString(str); e == nil {
+			return v, nil
+		}
+	}
+
+	return id, err
+}
+
+func parseHex(str string) (ID, error) {
+	return getCoder().decodeWith(str, Hex, false)
+}
+
+func parseBase32(str string) (ID, error) {
+	return getCoder().decodeWith(str, Base32, false)
+}
+
+// Encodes an ID as a JSON string in the encoding without a check character, or null if it's zero.
+func marshalJSON(id ID, e Encoding) ([]byte, error) {
+	if id == 0 {
+		return []byte{'n', 'u', 'l', 'l'}, nil
+	}
+
+	b := make([]byte, 0, e.Len()+2)
+	b = append(b, '"')
+	b = getCoder().appendWith(b, id, e, false)
+	b = append(b, '"')
+
+	return b, nil
+}
+
+// Decodes a JSON string with parse, or a JSON number or null like (*ID).UnmarshalJSON.
+func unmarshalJSON(b []byte, parse func(string) (ID, error)) (ID, error) {
+	if len(b) >= 2 && b[0] == '"' && b[len(b)-1] == '"' {
+		return parse(b2s(b[1 : len(b)-1]))
+	}
+
+	if len(b) == 4 && string(b) == "null" {
+		return 0, nil
+	}
+
+	return parseDecimal(b2s(b))
+}
@@ -0,0 +1,160 @@
+package hexid
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strconv"
+	"testing"
+)
+
+func ExampleNumericID() {
+	type event struct {
+		ID     NumericID `json:"id"`
+		Public HexID     `json:"public"`
+		Short  Base32ID  `json:"short"`
+	}
+
+	id := ID(0x1234567890abcdef)
+	b, _ := json.Marshal(event{ID: NumericID(id), Public: HexID(id), Short: Base32ID(id)})
+	fmt.Println(string(b))
+
+	// Output: {"id":1311768467294899695,"public":"bee99827fa22a2c1","short":"BXTCR4ZX258P1"}
+}
+
+func TestNumericID(t *testing.T) {
+	id := NumericID(Generate())
+	b, _ := json.Marshal(id)
+
+	if want := strconv.FormatUint(uint64(id), 10); string(b) != want || id.String() != want {
+		t.Fatalf("expected %s, got %s and %s", want, b, id)
+	}
+
+	for _, input := range []string{string(b), `"` + string(b) + `"`, `"` + id.ID().String() + `"`} {
+		var got NumericID
+
+		if err := json.Unmarshal([]byte(input), &got); err != nil || got != id {
+			t.Fatalf("expected %d from %s, got %d (%v)", id, input, got, err)
+		}
+	}
+
+	var got NumericID
+
+	if err := got.UnmarshalText([]byte(id.ID().String())); err != nil || got != id {
+		t.Fatalf("expected %d, got %d (%v)", id, got, err)
+	}
+
+	if err := json.Unmarshal([]byte("9223372036854775808"), &got); !errors.Is(err, ErrSignBit) {
+		t.Fatalf("expected ErrSignBit, got %v", err)
+	}
+}
+
+func TestNumericIDRoundTrip(t *testing.T) {
+	for v := range uint64(200) {
+		id := NumericID(1000000000000000 + v) // 16 digits, which are valid hex as well
+		text, _ := id.MarshalText()
+		b, _ := json.Marshal(id)
+
+		var fromText, fromJSON NumericID
+
+		if err := fromText.UnmarshalText(text); err != nil || fromText != id {
+			t.Fatalf("expected %d from %s, got %d (%v)", id, text, fromText, err)
+		}
+
+		if err := json.Unmarshal([]byte(`"`+string(b)+`"`), &fromJSON); err != nil || fromJSON != id {
+			t.Fatalf("expected %d from %q, got %d (%v)", id, b, fromJSON, err)
+		}
+	}
+}
+
+func TestEncodedIDs(t *testing.T) {
+	c, _ := DefaultCoder.WithEncoding(Base58)
+	SetCoder(c)
+	t.Cleanup(func() { SetCoder(nil) })
+
+	id := Generate()
+
+	for _, tc := range []struct {
+		v interface {
+			json.Marshaler
+			fmt.Stringer
+		}
+		ptr  json.Unmarshaler
+		want string
+	}{
+		{HexID(id), new(HexID), DefaultCoder.Encode(id)},
+		{Base32ID(id), new(Base32ID), string(id.AppendBase32(nil))},
+	} {
+		b, _ := json.Marshal(tc.v)
+
+		if want := `"` + tc.want + `"`; string(b) != want || tc.v.String() != tc.want {
+			t.Fatalf("expected %s, got %s and %s", want, b, tc.v)
+		}
+
+		for _, input := range []string{string(b), strconv.FormatUint(uint64(id), 10)} {
+			if err := json.Unmarshal([]byte(input), tc.ptr); err != nil {
+				t.Fatalf("expected %s to decode, got %v", input, err)
+			}
+		}
+
+		if err := json.Unmarshal([]byte(`"`+id.String()+`"`), tc.ptr); err == nil {
+			t.Fatalf("expected base58 to fail")
+		}
+	}
+
+	var h HexID
+
+	if err := json.Unmarshal([]byte(`"`+DefaultCoder.Encode(id)+`"`), &h); err != nil || h.ID() != id {
+		t.Fatalf("expected %d, got %d (%v)", id, h, err)
+	}
+
+	if b, _ := json.Marshal(HexID(0)); string(b) != "null" {
+		t.Fatalf("expected null, got %s", b)
+	}
+}
+
+func TestEncodedIDsChecked(t *testing.T) {
+	SetCoder(DefaultCoder.WithChecksum())
+	t.Cleanup(func() { SetCoder(nil) })
+
+	id := Generate()
+
+	for _, tc := range []struct {
+		v    fmt.Stringer
+		ptr  json.Unmarshaler
+		want string
+	}{
+		{HexID(id), new(HexID), DefaultCoder.Encode(id)},
+		{Base32ID(id), new(Base32ID), string(DefaultCoder.appendWith(nil, id, Base32, false))},
+	} {
+		if got := tc.v.String(); got != tc.want {
+			t.Fatalf("expected %s without a check character, got %s", tc.want, got)
+		}
+
+		if err := tc.ptr.UnmarshalJSON([]byte(`"` + tc.want + `"`)); err != nil {
+			t.Fatalf("expected %s to decode, got %v", tc.want, err)
+		}
+	}
+}
+
+func TestFormatIDValue(t *testing.T) {
+	id := Generate()
+
+	want, _ := id.Value()
+
+	for _, got := range []func() (any, error){
+		func() (any, error) { return NumericID(id).Value() },
+		func() (any, error) { return HexID(id).Value() },
+		func() (any, error) { return Base32ID(id).Value() },
+	} {
+		if v, err := got(); err != nil || v != want {
+			t.Fatalf("expected %v, got %v (%v)", want, v, err)
+		}
+	}
+
+	var n NumericID
+
+	if err := n.Scan(id.Int64()); err != nil || n.ID() != id {
+		t.Fatalf("expected %d, got %d (%v)", id, n, err)
+	}
+}
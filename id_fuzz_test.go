@@ -20,7 +20,7 @@ func FuzzID(f *testing.F) {
 		seq = seq & 0x7FFF // 15 bits (0–32767)
 
 		ts := time.Unix(sec, nsec)
-		id := newID(ts, node, seq)
+		id := DefaultLayout.fromTime(ts, uint16(node), uint32(seq))
 
 		// Verify basic fields
 		if got, want := id.Unix(), uint32(ts.Unix()); got != want {
@@ -27,7 +27,7 @@ func TestIDEncodingDecoding(t *testing.T) {
 
 	for _, tc := range testCases {
 		t.Run(tc.name, func(t *testing.T) {
-			id := newID(tc.timestamp, tc.node, tc.seq)
+			id := DefaultLayout.fromTime(tc.timestamp, uint16(tc.node), uint32(tc.seq))
 			t.Logf("raw id: %064b", id)
 
 			// Verify timestamp bits
@@ -77,8 +77,8 @@ func TestSequenceOverflow(t *testing.T) {
 	node := uint8(5)
 
 	// Force sequence to just before overflow
-	id1 := newID(ts, node, 0x7FFF) // 32767
-	id2 := newID(ts, node, 0x8000) // 32768 (should wrap)
+	id1 := DefaultLayout.fromTime(ts, uint16(node), 0x7FFF) // 32767
+	id2 := DefaultLayout.fromTime(ts, uint16(node), 0x8000) // 32768 (should wrap)
 
 	if id1.Seq() != 0x7FFF {
 		t.Fatalf("expected 0x7FFF before overflow, got %d", id1.Seq())
@@ -134,3 +134,26 @@ func TestID_TimestampAndEntropy(t *testing.T) {
 	t.Logf("id:      %064b", id)
 	t.Logf("entropy: %031b", entropy)
 }
+
+func TestIDValid(t *testing.T) {
+	now := time.Now()
+
+	for _, tc := range []struct {
+		id    ID
+		valid bool
+	}{
+		{Generate(), true},
+		{DefaultLayout.fromTime(now, 1, 0), true},
+		{DefaultLayout.fromTime(now.Add(10*time.Minute), 63, 32767), true},
+		{DefaultLayout.fromTime(now.Add(2*time.Hour), 1, 0), false},
+		{DefaultLayout.fromTime(now, 1, 0) | 1000<<21, false}, // 1000 ms
+		{HashedID("foo"), true},
+		{HashedID("foo") | 1<<63, false},
+		{DefaultLayout.fromTime(now, 1, 0) | 1<<63, false},
+		{0, false},
+	} {
+		if got := tc.id.Valid(); got != tc.valid {
+			t.Errorf("expected Valid() of %064b to be %t", uint64(tc.id), tc.valid)
+		}
+	}
+}
@@ -0,0 +1,186 @@
+package hexid
+
+import (
+	"errors"
+	"fmt"
+	"time"
+)
+
+// Number of bits of the millisecond field, which is the same for all layouts.
+const msBits = 10
+
+// Smallest number of bits that a layout must leave for the seconds field (~34 years).
+const minSecBits = 30
+
+// ErrLayoutExceeded is returned by TryID when the clock is past the End of the generator's layout,
+// where the seconds field would wrap around.
+var ErrLayoutExceeded = errors.New("time is past the end of the layout")
+
+// A Layout describes how the 63 bits of an ID are split between its fields:
+//
+//	[seconds][10-bit milliseconds][NodeBits node][SeqBits sequence]
+//
+// The seconds field gets whatever bits are left. Trading sequence bits for node bits allows for
+// more nodes with fewer IDs per millisecond each, and vice versa. A custom layout must be shared
+// by everything that reads the fields of IDs, as the methods of ID assume DefaultLayout, and so do
+// the SQL functions.
+type Layout struct {
+	NodeBits uint8 // Between 1 and 16
+	SeqBits  uint8 // Between 1 and 21
+	Epoch    Epoch // Point in time that the seconds field counts from
+}
+
+// The layout of IDs unless configured otherwise: 32-bit seconds, 6-bit node and 15-bit sequence.
+var DefaultLayout = Layout{NodeBits: 6, SeqBits: 15}
+
+// Validate reports whether the layout can be used by a generator.
+func (l Layout) Validate() error {
+	switch {
+	case l.NodeBits < 1 || l.NodeBits > 16:
+		return errors.New("layout must have between 1 and 16 node bits")
+	case l.SeqBits < 1 || l.SeqBits > 21:
+		return errors.New("layout must have between 1 and 21 sequence bits")
+	case l.secBits() < minSecBits:
+		return fmt.Errorf("layout leaves %d bits for seconds, at least %d are needed", l.secBits(), minSecBits)
+	}
+
+	return nil
+}
+
+// End returns the point in time when the seconds field of the layout runs out, counted from its
+// epoch. IDs can only be generated before it.
+func (l Layout) End() time.Time {
+	return time.Unix(int64(l.Epoch)+1<<l.secBits(), 0)
+}
+
+// Highest node ID of the layout.
+func (l Layout) MaxNode() uint16 {
+	return 1<<l.NodeBits - 1
+}
+
+// Number of sequence numbers per millisecond per node.
+func (l Layout) SeqLimit() uint32 {
+	return 1 << l.SeqBits
+}
+
+// Unix returns the Unix timestamp in seconds of an ID.
+func (l Layout) Unix(id ID) int64 {
+	return int64(id>>l.secShift()) + int64(l.Epoch)
+}
+
+// Millis returns the millisecond part within the second of an ID.
+func (l Layout) Millis(id ID) uint16 {
+	return uint16(id>>l.msShift()) & (1<<msBits - 1)
+}
+
+// Node returns the node ID of an ID.
+func (l Layout) Node(id ID) uint16 {
+	return uint16(id>>l.SeqBits) & l.MaxNode()
+}
+
+// Seq returns the sequence number of an ID.
+func (l Layout) Seq(id ID) uint32 {
+	return uint32(id) & (l.SeqLimit() - 1)
+}
+
+// Hashed reports whether an ID has node ID = 0, which is reserved for hashed IDs.
+func (l Layout) Hashed(id ID) bool {
+	return l.Node(id) == 0
+}
+
+// Time reconstructs the approximate creation time of an ID.
+func (l Layout) Time(id ID) time.Time {
+	if l.Hashed(id) {
+		return time.Time{}
+	}
+
+	return time.Unix(l.Unix(id), int64(l.Millis(id))*1_000_000)
+}
+
+// How far in the future the timestamp of a valid ID may be, to allow for clock skew between nodes.
+const maxSkew = time.Hour
+
+// Valid reports whether an ID is plausible in this layout: its sign bit must be 0, and unless it's
+// hashed (node ID = 0), its milliseconds must be below 1000 and its timestamp no more than an hour
+// into the future. The zero ID is not valid.
+func (l Layout) Valid(id ID) bool {
+	if id == 0 || id>>63 != 0 {
+		return false
+	}
+
+	if l.Hashed(id) {
+		return true
+	}
+
+	return l.Millis(id) < 1000 && l.Unix(id) <= time.Now().Add(maxSkew).Unix()
+}
+
+// HashedID produces a deterministic 63-bit ID from one or more strings, with node ID = 0 in this
+// layout. See HashedID.
+func (l Layout) HashedID(s ...string) ID {
+	return ID(uint64(HashedID(s...)) &^ (uint64(l.MaxNode()) << l.SeqBits))
+}
+
+func (l Layout) secBits() int {
+	return 63 - msBits - int(l.NodeBits) - int(l.SeqBits)
+}
+
+func (l Layout) msShift() uint8 {
+	return l.SeqBits + l.NodeBits
+}
+
+func (l Layout) secShift() uint8 {
+	return l.msShift() + msBits
+}
+
+// Composes an ID from its fields. Seconds beyond the width of the seconds field wrap around, and
+// the sign bit is always 0.
+func (l Layout) compose(secs, msecs uint64, node uint16, seq uint32) ID {
+	const mask63 = 0x7FFFFFFFFFFFFFFF
+
+	id := secs<<l.secShift() |
+		msecs<<l.msShift() |
+		uint64(node&l.MaxNode())<<l.SeqBits |
+		uint64(seq&(l.SeqLimit()-1))
+
+	return ID(id & mask63)
+}
+
+// Composes an ID from milliseconds since the layout's epoch.
+func (l Layout) fromMilli(ms int64, node uint16, seq uint32) ID {
+	return l.compose(uint64(ms/1000), uint64(ms%1000), node, seq)
+}
+
+// Composes an ID from a timestamp that is already shifted to the layout's epoch.
+func (l Layout) fromTime(ts time.Time, node uint16, seq uint32) ID {
+	return l.compose(uint64(ts.Unix()), uint64(ts.Nanosecond()/1_000_000), node, seq)
+}
+
+// Layout of the generated IDs. Defaults to DefaultLayout. A layout without an epoch keeps the one
+// set by WithEpoch, and fails if it has a different one. The node ID must fit within the node bits
+// of the layout.
+func WithLayout(l Layout) Option {
+	return func(c *config) error {
+		if err := l.Validate(); err != nil {
+			return err
+		}
+
+		if l.Epoch == UnixEpoch {
+			l.Epoch = c.layout.Epoch
+		} else if c.layout.Epoch != UnixEpoch && c.layout.Epoch != l.Epoch {
+			return errors.New("layout epoch conflicts with the epoch set by WithEpoch")
+		}
+
+		c.layout = l
+		return nil
+	}
+}
+
+// Layout of the generator's IDs.
+func (c *config) Layout() Layout {
+	if c.layout.NodeBits == 0 {
+		return Layout{NodeBits: DefaultLayout.NodeBits, SeqBits: DefaultLayout.SeqBits, Epoch: c.layout.Epoch}
+	}
+
+	return c.layout
+}
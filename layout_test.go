@@ -0,0 +1,191 @@
+package hexid
+
+import (
+	"testing"
+	"time"
+)
+
+func TestDefaultLayout(t *testing.T) {
+	g, _ := New(WithNode(42))
+
+	for range 100 {
+		id := g.ID()
+		l := DefaultLayout
+
+		if l.Unix(id) != int64(id.Unix()) || l.Millis(id) != id.Millis() || l.Node(id) != uint16(id.Node()) || l.Seq(id) != uint32(id.Seq()) {
+			t.Fatalf("expected the default layout to read %s like ID does", id)
+		}
+
+		if !l.Time(id).Equal(id.Time()) {
+			t.Fatalf("expected %v, got %v", id.Time(), l.Time(id))
+		}
+	}
+
+	if l := g.Layout(); l != DefaultLayout {
+		t.Fatalf("expected the default layout, got %+v", l)
+	}
+
+	if h := DefaultLayout.HashedID("foo"); h != HashedID("foo") {
+		t.Fatalf("expected %s, got %s", HashedID("foo"), h)
+	}
+}
+
+func TestLayout(t *testing.T) {
+	l := Layout{
+		NodeBits: 10,
+		SeqBits:  12,
+		Epoch:    NewEpoch(time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)),
+	}
+
+	ts := time.Date(2025, 1, 1, 0, 0, 0, 123_000_000, time.UTC)
+	g, err := New(WithLayout(l), WithNode(1000), WithSequence(0), WithClock(NewManualClock(ts)))
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ids := g.GenerateN(nil, int(l.SeqLimit())+1)
+	ids = append(ids, g.IDFromTime(ts))
+
+	for i, id := range ids[:len(ids)-1] {
+		want := ts.Add(time.Duration(i/int(l.SeqLimit())) * time.Millisecond)
+
+		if got := l.Time(id); !got.Equal(want) {
+			t.Fatalf("expected %v, got %v", want, got)
+		}
+
+		if got := l.Node(id); got != 1000 {
+			t.Fatalf("expected node 1000, got %d", got)
+		}
+
+		if got, want := l.Seq(id), uint32(i)%l.SeqLimit(); got != want {
+			t.Fatalf("expected sequence %d, got %d", want, got)
+		}
+
+		if id.Int64() < 0 {
+			t.Fatalf("expected a positive ID, got %d", id.Int64())
+		}
+	}
+
+	if got := l.Time(ids[len(ids)-1]); !got.Equal(ts) {
+		t.Fatalf("expected %v, got %v", ts, got)
+	}
+
+	if h := l.HashedID("foo"); !l.Hashed(h) || !l.Time(h).IsZero() {
+		t.Fatalf("expected %s to be hashed in the layout", h)
+	}
+}
+
+func TestLayoutValidate(t *testing.T) {
+	invalid := []Layout{
+		{},
+		{NodeBits: 6},
+		{SeqBits: 15},
+		{NodeBits: 17, SeqBits: 1},
+		{NodeBits: 1, SeqBits: 22},
+		{NodeBits: 12, SeqBits: 12},
+	}
+
+	for _, l := range invalid {
+		if l.Validate() == nil {
+			t.Errorf("expected %+v to be invalid", l)
+		}
+
+		if _, err := New(WithLayout(l)); err == nil {
+			t.Errorf("expected WithLayout(%+v) to fail", l)
+		}
+	}
+
+	if err := (Layout{NodeBits: 12, SeqBits: 11}).Validate(); err != nil {
+		t.Errorf("expected 30 seconds bits to be valid, got %v", err)
+	}
+
+	if _, err := New(WithNode(64)); err == nil {
+		t.Error("expected node 64 to not fit the default layout")
+	}
+
+	if _, err := New(WithLayout(Layout{NodeBits: 7, SeqBits: 14}), WithNode(127)); err != nil {
+		t.Errorf("expected node 127 to fit a 7-bit node, got %v", err)
+	}
+
+	if _, err := NewShardedGenerator(8, WithLayout(Layout{NodeBits: 6, SeqBits: 2})); err == nil {
+		t.Error("expected more shards than sequence numbers to fail")
+	}
+}
+
+func TestLayoutEnd(t *testing.T) {
+	l := Layout{NodeBits: 10, SeqBits: 13}
+
+	if end := l.End(); !end.Equal(time.Unix(1<<30, 0)) {
+		t.Fatalf("expected the layout to end at %v, got %v", time.Unix(1<<30, 0), end)
+	}
+
+	if end := DefaultLayout.End(); end.Year() != 2106 {
+		t.Fatalf("expected the default layout to end in 2106, got %v", end)
+	}
+
+	// 30 bits of seconds from the unix epoch ran out in 2004
+	if _, err := New(WithLayout(l)); err == nil {
+		t.Fatal("expected a layout that has ended to fail")
+	}
+
+	l.Epoch = NewEpoch(time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC))
+
+	if _, err := New(WithLayout(l)); err != nil {
+		t.Fatal(err)
+	}
+
+	// The clock passes the end of the layout
+	c := NewManualClock(l.End().Add(-time.Millisecond))
+	g, err := New(WithLayout(l), WithClock(c))
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err = g.TryID(); err != nil {
+		t.Fatal(err)
+	}
+
+	c.Advance(time.Millisecond)
+
+	if _, err = g.TryID(); err != ErrLayoutExceeded {
+		t.Fatalf("expected ErrLayoutExceeded, got %v", err)
+	}
+
+	if err = g.TryFill(make([]ID, 4)); err != ErrLayoutExceeded {
+		t.Fatalf("expected ErrLayoutExceeded, got %v", err)
+	}
+}
+
+func TestLayoutEpoch(t *testing.T) {
+	epoch := NewEpoch(time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC))
+	other := NewEpoch(time.Date(2021, 1, 1, 0, 0, 0, 0, time.UTC))
+	l := Layout{NodeBits: 6, SeqBits: 15}
+
+	// A layout without an epoch keeps the one set by WithEpoch, whatever the order
+	for _, opts := range [][]Option{
+		{WithEpoch(epoch), WithLayout(l)},
+		{WithLayout(l), WithEpoch(epoch)},
+		{WithEpoch(epoch), WithLayout(Layout{NodeBits: 6, SeqBits: 15, Epoch: epoch})},
+	} {
+		g, err := New(opts...)
+
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if got := g.Epoch(); got != epoch {
+			t.Fatalf("expected epoch %d, got %d", epoch, got)
+		}
+	}
+
+	for _, opts := range [][]Option{
+		{WithEpoch(other), WithLayout(Layout{NodeBits: 6, SeqBits: 15, Epoch: epoch})},
+		{WithLayout(Layout{NodeBits: 6, SeqBits: 15, Epoch: epoch}), WithEpoch(other)},
+	} {
+		if _, err := New(opts...); err == nil {
+			t.Fatal("expected conflicting epochs to fail")
+		}
+	}
+}
@@ -0,0 +1,180 @@
+package hexid
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/rand"
+	"os"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+var (
+	// ErrLeaseLost is returned when a node lease has expired or has been taken over by another owner.
+	ErrLeaseLost = errors.New("node lease lost")
+
+	// ErrNoFreeNode is returned by a LeaseStore when all node IDs in its range are leased.
+	ErrNoFreeNode = errors.New("no free node")
+)
+
+// A LeaseStore keeps track of which node IDs are leased by whom, and must be safe for use by
+// multiple processes at once.
+type LeaseStore interface {
+	// Acquire leases any free node ID in the range of the store, which is 1 to 63 unless
+	// configured otherwise, to owner for the duration of ttl. Expired leases are free. Fails with
+	// ErrNoFreeNode if there is no free node.
+	Acquire(ctx context.Context, owner string, ttl time.Duration) (node uint16, err error)
+
+	// Renew extends the lease of node to ttl from now. Fails with ErrLeaseLost if the node is
+	// leased by another owner.
+	Renew(ctx context.Context, node uint16, owner string, ttl time.Duration) error
+
+	// Release frees node, unless it's leased by another owner.
+	Release(ctx context.Context, node uint16, owner string) error
+}
+
+// A NodeLeaser holds a lease on a node ID, and renews it in the background until closed. A
+// generator using the leased node refuses to generate IDs while the lease isn't valid.
+type NodeLeaser struct {
+	store  LeaseStore
+	owner  string
+	ttl    time.Duration
+	node   uint16
+	valid  atomic.Bool
+	cancel context.CancelFunc
+	done   chan struct{}
+	mu     sync.Mutex
+	err    error
+}
+
+// Lease a free node ID from store, and keep renewing it every third of ttl until closed. The
+// lease is considered lost when it hasn't been renewed within ttl, so any clock drift between the
+// process and the store must be a fraction of ttl.
+func LeaseNode(ctx context.Context, store LeaseStore, ttl time.Duration) (l *NodeLeaser, err error) {
+	if ttl <= 0 {
+		return nil, errors.New("lease ttl must be positive")
+	}
+
+	l = &NodeLeaser{
+		store: store,
+		owner: leaseOwner(),
+		ttl:   ttl,
+		done:  make(chan struct{}),
+	}
+
+	start := time.Now()
+
+	if l.node, err = store.Acquire(ctx, l.owner, ttl); err != nil {
+		return nil, fmt.Errorf("failed to acquire node lease: %w", err)
+	}
+
+	l.valid.Store(true)
+
+	var hbCtx context.Context
+	hbCtx, l.cancel = context.WithCancel(context.Background())
+	go l.heartbeat(hbCtx, start.Add(ttl))
+
+	return
+}
+
+// Leased node ID.
+func (l *NodeLeaser) Node() uint16 {
+	return l.node
+}
+
+// Unique owner of the lease.
+func (l *NodeLeaser) Owner() string {
+	return l.owner
+}
+
+// Valid reports whether the lease is still held.
+func (l *NodeLeaser) Valid() bool {
+	return l.valid.Load()
+}
+
+// Err returns the error of the last renewal, if it failed.
+func (l *NodeLeaser) Err() error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return l.err
+}
+
+// Close stops renewing the lease and releases the node.
+func (l *NodeLeaser) Close() error {
+	l.cancel()
+	<-l.done
+	l.valid.Store(false)
+
+	ctx, cancel := context.WithTimeout(context.Background(), l.ttl)
+	defer cancel()
+
+	return l.store.Release(ctx, l.node, l.owner)
+}
+
+func (l *NodeLeaser) heartbeat(ctx context.Context, expires time.Time) {
+	defer close(l.done)
+
+	ticker := time.NewTicker(l.ttl / 3)
+	defer ticker.Stop()
+
+	timer := time.NewTimer(time.Until(expires))
+	defer timer.Stop()
+
+	for {
+		select {
+
+		case <-ctx.Done():
+			return
+
+		case <-timer.C:
+			l.valid.Store(false)
+
+		case <-ticker.C:
+			start := time.Now()
+
+			// Never wait for the store beyond the expiry, as the lease would still be considered valid
+			rctx, cancel := context.WithDeadline(ctx, expires)
+			err := l.store.Renew(rctx, l.node, l.owner, l.ttl)
+			cancel()
+
+			// Another owner holds the node, so it must not be used until it expires
+			if errors.Is(err, ErrLeaseLost) {
+				l.valid.Store(false)
+			}
+
+			l.mu.Lock()
+			l.err = err
+			l.mu.Unlock()
+
+			if err == nil {
+				expires = start.Add(l.ttl)
+				timer.Reset(time.Until(expires))
+				l.valid.Store(true)
+			}
+		}
+	}
+}
+
+// Node ID of the generator, leased by l. The generator refuses to generate IDs with
+// ErrLeaseLost while the lease isn't valid.
+func WithLease(l *NodeLeaser) Option {
+	return func(c *config) error {
+		if l == nil {
+			return errors.New("lease must not be nil")
+		}
+
+		if err := WithNode(l.Node())(c); err != nil {
+			return err
+		}
+
+		c.lease = l
+		return nil
+	}
+}
+
+func leaseOwner() string {
+	hostname, _ := os.Hostname()
+	return fmt.Sprintf("%s:%d:%08x", hostname, os.Getpid(), rand.Uint32())
+}
@@ -0,0 +1,215 @@
+package hexid
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+)
+
+var _ LeaseStore = (*FileLeaseStore)(nil)
+
+// How long a lock file may exist before it's considered abandoned by a crashed process. It must
+// exceed the longest time that a process holds the lock, which is only a few file operations, or
+// a slow process might have its lock taken over while it still holds it.
+const fileLeaseLockStale = 10 * time.Second
+
+// A LeaseStore that keeps one file per leased node in a directory, e.g. on a volume shared by all
+// processes. The directory is guarded by a lock file, which relies on exclusive file creation and
+// therefore also works on network file systems. A lock file that is older than 10 seconds is
+// considered abandoned by a crashed process, and taken over.
+type FileLeaseStore struct {
+	dir     string
+	maxNode uint16
+}
+
+// Create a file lease store in dir, which is created if it doesn't exist.
+func NewFileLeaseStore(dir string) (*FileLeaseStore, error) {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, err
+	}
+
+	return &FileLeaseStore{dir: dir, maxNode: DefaultLayout.MaxNode()}, nil
+}
+
+// Lease node IDs between 1 and max, e.g. the MaxNode of a custom layout. Defaults to 63, the
+// highest node ID of DefaultLayout. All processes sharing the directory should use the same max.
+func (s *FileLeaseStore) SetMaxNode(max uint16) {
+	s.maxNode = max
+}
+
+// Acquire implements LeaseStore.
+func (s *FileLeaseStore) Acquire(ctx context.Context, owner string, ttl time.Duration) (node uint16, err error) {
+	err = s.locked(ctx, func() error {
+		now := time.Now()
+
+		for n := uint16(1); n <= s.maxNode; n++ {
+			cur, expires, err := s.read(n)
+
+			if err != nil {
+				return err
+			}
+
+			if cur == "" || cur == owner || now.After(expires) {
+				node = n
+				return s.write(n, owner, now.Add(ttl))
+			}
+		}
+
+		return ErrNoFreeNode
+	})
+
+	return
+}
+
+// Renew implements LeaseStore.
+func (s *FileLeaseStore) Renew(ctx context.Context, node uint16, owner string, ttl time.Duration) error {
+	return s.locked(ctx, func() error {
+		cur, _, err := s.read(node)
+
+		if err != nil {
+			return err
+		}
+
+		if cur != "" && cur != owner {
+			return ErrLeaseLost
+		}
+
+		return s.write(node, owner, time.Now().Add(ttl))
+	})
+}
+
+// Release implements LeaseStore.
+func (s *FileLeaseStore) Release(ctx context.Context, node uint16, owner string) error {
+	return s.locked(ctx, func() error {
+		cur, _, err := s.read(node)
+
+		if err != nil || cur != owner {
+			return err
+		}
+
+		return os.Remove(s.path(node))
+	})
+}
+
+func (s *FileLeaseStore) path(node uint16) string {
+	return filepath.Join(s.dir, fmt.Sprintf("node-%02d.lease", node))
+}
+
+// Reads the owner and expiry of a node. An empty owner means that the node is free.
+func (s *FileLeaseStore) read(node uint16) (owner string, expires time.Time, err error) {
+	b, err := os.ReadFile(s.path(node))
+
+	if err != nil {
+		if errors.Is(err, fs.ErrNotExist) {
+			err = nil
+		}
+
+		return
+	}
+
+	owner, exp, ok := strings.Cut(strings.TrimSpace(b2s(b)), "\n")
+
+	if !ok {
+		return "", time.Time{}, fmt.Errorf("corrupt lease file: %s", s.path(node))
+	}
+
+	ns, err := strconv.ParseInt(exp, 10, 64)
+
+	if err != nil {
+		return "", time.Time{}, fmt.Errorf("corrupt lease file: %s", s.path(node))
+	}
+
+	return owner, time.Unix(0, ns), nil
+}
+
+func (s *FileLeaseStore) write(node uint16, owner string, expires time.Time) error {
+	if owner == "" || strings.ContainsRune(owner, '\n') {
+		return fmt.Errorf("invalid lease owner: %q", owner)
+	}
+
+	tmp := s.path(node) + ".tmp"
+	data := fmt.Sprintf("%s\n%d\n", owner, expires.UnixNano())
+
+	if err := os.WriteFile(tmp, []byte(data), 0o644); err != nil {
+		return err
+	}
+
+	return os.Rename(tmp, s.path(node))
+}
+
+// Runs fn while holding the lock of the directory.
+func (s *FileLeaseStore) locked(ctx context.Context, fn func() error) error {
+	lock := filepath.Join(s.dir, ".lock")
+
+	for {
+		f, err := os.OpenFile(lock, os.O_CREATE|os.O_EXCL|os.O_WRONLY, 0o644)
+
+		if err == nil {
+			f.Close()
+			defer os.Remove(lock)
+			return fn()
+		}
+
+		if !errors.Is(err, fs.ErrExist) {
+			return err
+		}
+
+		if info, err := os.Stat(lock); err == nil && time.Since(info.ModTime()) > fileLeaseLockStale {
+			if err = breakLock(lock, info); err != nil {
+				return err
+			}
+
+			continue
+		}
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(10 * time.Millisecond):
+		}
+	}
+}
+
+// Removes a stale lock file. Checking that a lock is stale and removing it is not atomic, so
+// another process could take over the same stale lock and create a new one in between, which
+// must not be removed. Therefore processes that break locks are serialized by a second lock file,
+// and the lock is only removed if it's still the same stale file.
+func breakLock(lock string, stale fs.FileInfo) error {
+	brk := lock + ".break"
+	f, err := os.OpenFile(brk, os.O_CREATE|os.O_EXCL|os.O_WRONLY, 0o644)
+
+	if err != nil {
+		if !errors.Is(err, fs.ErrExist) {
+			return err
+		}
+
+		// The second lock is only held for an instant, unless its process crashed
+		if info, err := os.Stat(brk); err == nil && time.Since(info.ModTime()) > fileLeaseLockStale {
+			os.Remove(brk)
+		}
+
+		time.Sleep(time.Millisecond)
+		return nil
+	}
+
+	f.Close()
+	defer os.Remove(brk)
+
+	info, err := os.Stat(lock)
+
+	if err != nil || !os.SameFile(info, stale) || !info.ModTime().Equal(stale.ModTime()) {
+		return nil
+	}
+
+	if err = os.Remove(lock); errors.Is(err, fs.ErrNotExist) {
+		err = nil
+	}
+
+	return err
+}
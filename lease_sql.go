@@ -0,0 +1,115 @@
+package hexid
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+)
+
+var _ LeaseStore = (*SQLLeaseStore)(nil)
+
+// A LeaseStore that keeps leases in a PostgreSQL table, with expiry based on the database's clock:
+//
+//	CREATE TABLE hexid_leases (
+//	  node       integer     PRIMARY KEY,
+//	  owner      text        NOT NULL,
+//	  expires_at timestamptz NOT NULL
+//	);
+type SQLLeaseStore struct {
+	db      *sql.DB
+	table   string
+	maxNode uint16
+}
+
+// Create an SQL lease store on the provided table, e.g. "hexid_leases". The table name is not
+// escaped, and must be trusted.
+func NewSQLLeaseStore(db *sql.DB, table string) *SQLLeaseStore {
+	return &SQLLeaseStore{db: db, table: table, maxNode: DefaultLayout.MaxNode()}
+}
+
+// Lease node IDs between 1 and max, e.g. the MaxNode of a custom layout. Defaults to 63, the
+// highest node ID of DefaultLayout. All processes sharing the table should use the same max.
+func (s *SQLLeaseStore) SetMaxNode(max uint16) {
+	s.maxNode = max
+}
+
+// Create the lease table unless it already exists.
+func (s *SQLLeaseStore) CreateTable(ctx context.Context) error {
+	_, err := s.db.ExecContext(ctx, s.createQuery())
+	return err
+}
+
+// Acquire implements LeaseStore.
+func (s *SQLLeaseStore) Acquire(ctx context.Context, owner string, ttl time.Duration) (node uint16, err error) {
+	query := s.acquireQuery()
+
+	// A concurrent acquirer might pick the same node, in which case we try again
+	for range 3 {
+		err = s.db.QueryRowContext(ctx, query, owner, ttl.Milliseconds(), s.maxNode).Scan(&node)
+
+		if err != sql.ErrNoRows {
+			return
+		}
+	}
+
+	return 0, ErrNoFreeNode
+}
+
+// Renew implements LeaseStore.
+func (s *SQLLeaseStore) Renew(ctx context.Context, node uint16, owner string, ttl time.Duration) error {
+	res, err := s.db.ExecContext(ctx, s.renewQuery(), node, owner, ttl.Milliseconds())
+
+	if err != nil {
+		return err
+	}
+
+	if n, err := res.RowsAffected(); err != nil {
+		return err
+	} else if n == 0 {
+		return ErrLeaseLost
+	}
+
+	return nil
+}
+
+// Release implements LeaseStore.
+func (s *SQLLeaseStore) Release(ctx context.Context, node uint16, owner string) error {
+	_, err := s.db.ExecContext(ctx, s.releaseQuery(), node, owner)
+	return err
+}
+
+func (s *SQLLeaseStore) createQuery() string {
+	return fmt.Sprintf(`CREATE TABLE IF NOT EXISTS %s (
+  node       integer     PRIMARY KEY,
+  owner      text        NOT NULL,
+  expires_at timestamptz NOT NULL
+)`, s.table)
+}
+
+// Leases the lowest node between 1 and $3 that is free, expired or already leased by owner $1, for
+// $2 milliseconds. Returns no rows if a concurrent acquirer got the node first.
+func (s *SQLLeaseStore) acquireQuery() string {
+	return fmt.Sprintf(`INSERT INTO %[1]s AS l (node, owner, expires_at)
+SELECT n, $1, now() + $2 * interval '1 millisecond'
+FROM generate_series(1, $3) AS n
+WHERE NOT EXISTS (
+  SELECT 1 FROM %[1]s WHERE node = n AND owner <> $1 AND expires_at >= now()
+)
+ORDER BY n
+LIMIT 1
+ON CONFLICT (node) DO UPDATE SET owner = excluded.owner, expires_at = excluded.expires_at
+WHERE l.owner = excluded.owner OR l.expires_at < now()
+RETURNING node`, s.table)
+}
+
+func (s *SQLLeaseStore) renewQuery() string {
+	return fmt.Sprintf(`INSERT INTO %s AS l (node, owner, expires_at)
+VALUES ($1, $2, now() + $3 * interval '1 millisecond')
+ON CONFLICT (node) DO UPDATE SET expires_at = excluded.expires_at
+WHERE l.owner = excluded.owner`, s.table)
+}
+
+func (s *SQLLeaseStore) releaseQuery() string {
+	return fmt.Sprintf(`DELETE FROM %s WHERE node = $1 AND owner = $2`, s.table)
+}
@@ -0,0 +1,419 @@
+package hexid
+
+import (
+	"context"
+	"errors"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+var _ LeaseStore = (*memoryLeaseStore)(nil)
+
+type memoryLeaseStore struct {
+	mu     sync.Mutex
+	owners map[uint16]string
+	fail   bool
+}
+
+func (s *memoryLeaseStore) Acquire(_ context.Context, owner string, _ time.Duration) (uint16, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.owners == nil {
+		s.owners = make(map[uint16]string)
+	}
+
+	for n := uint16(1); n <= 63; n++ {
+		if cur, ok := s.owners[n]; !ok || cur == owner {
+			s.owners[n] = owner
+			return n, nil
+		}
+	}
+
+	return 0, ErrNoFreeNode
+}
+
+func (s *memoryLeaseStore) Renew(_ context.Context, node uint16, owner string, _ time.Duration) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.fail {
+		return errors.New("store unavailable")
+	}
+
+	if s.owners[node] != owner {
+		return ErrLeaseLost
+	}
+
+	return nil
+}
+
+func (s *memoryLeaseStore) Release(_ context.Context, node uint16, owner string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.owners[node] == owner {
+		delete(s.owners, node)
+	}
+
+	return nil
+}
+
+func (s *memoryLeaseStore) set(fn func()) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	fn()
+}
+
+func waitFor(t *testing.T, cond func() bool) {
+	t.Helper()
+	deadline := time.Now().Add(time.Second)
+
+	for !cond() {
+		if time.Now().After(deadline) {
+			t.Fatal("timed out")
+		}
+
+		time.Sleep(time.Millisecond)
+	}
+}
+
+func TestNodeLeaser(t *testing.T) {
+	store := &memoryLeaseStore{}
+	ctx := context.Background()
+
+	l1, err := LeaseNode(ctx, store, 30*time.Millisecond)
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	l2, err := LeaseNode(ctx, store, 30*time.Millisecond)
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	defer l2.Close()
+
+	if l1.Node() == l2.Node() {
+		t.Fatalf("both leasers got node %d", l1.Node())
+	}
+
+	if err = l1.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	if l1.Valid() {
+		t.Fatal("expected a closed lease to be invalid")
+	}
+
+	l3, err := LeaseNode(ctx, store, 30*time.Millisecond)
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	defer l3.Close()
+
+	if l3.Node() != l1.Node() {
+		t.Fatalf("expected released node %d to be reused, got %d", l1.Node(), l3.Node())
+	}
+}
+
+func TestNodeLeaserGenerator(t *testing.T) {
+	store := &memoryLeaseStore{}
+	l, err := LeaseNode(context.Background(), store, 30*time.Millisecond)
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	defer l.Close()
+
+	g, err := New(WithLease(l))
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if id := g.ID(); DefaultLayout.Node(id) != l.Node() {
+		t.Fatalf("expected node %d, got %d", l.Node(), id.Node())
+	}
+
+	// The store stops responding, so the lease expires
+	store.set(func() { store.fail = true })
+	waitFor(t, func() bool { return !l.Valid() })
+
+	if _, err = g.TryID(); err != ErrLeaseLost {
+		t.Fatalf("expected ErrLeaseLost, got %v", err)
+	}
+
+	if l.Err() == nil {
+		t.Fatal("expected the renewal error to be reported")
+	}
+
+	// The store recovers, and the lease is renewed
+	store.set(func() { store.fail = false })
+	waitFor(t, l.Valid)
+
+	if _, err = g.TryID(); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err = New(WithLease(nil)); err == nil {
+		t.Fatal("expected an error for a nil lease")
+	}
+}
+
+func TestNodeLeaserTakeover(t *testing.T) {
+	store := &memoryLeaseStore{}
+	l, err := LeaseNode(context.Background(), store, 300*time.Millisecond)
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	defer l.Close()
+
+	// Another owner takes over the node, which must invalidate the lease as soon as the renewal
+	// notices, long before it would expire
+	store.set(func() { store.owners[l.Node()] = "someone else" })
+	waitFor(t, func() bool { return l.Err() != nil })
+
+	if !errors.Is(l.Err(), ErrLeaseLost) {
+		t.Fatalf("expected ErrLeaseLost, got %v", l.Err())
+	}
+
+	if l.Valid() {
+		t.Fatal("expected the lease to be invalid right after the takeover")
+	}
+}
+
+func TestFileLeaseStore(t *testing.T) {
+	ctx := context.Background()
+	s, err := NewFileLeaseStore(t.TempDir())
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	a, err := s.Acquire(ctx, "a", time.Minute)
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	b, err := s.Acquire(ctx, "b", time.Millisecond)
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if a == b {
+		t.Fatalf("both owners got node %d", a)
+	}
+
+	if err = s.Renew(ctx, a, "a", time.Minute); err != nil {
+		t.Fatal(err)
+	}
+
+	if err = s.Renew(ctx, a, "b", time.Minute); err != ErrLeaseLost {
+		t.Fatalf("expected ErrLeaseLost, got %v", err)
+	}
+
+	// The lease of b expires, so that c can take it over
+	time.Sleep(2 * time.Millisecond)
+
+	if c, err := s.Acquire(ctx, "c", time.Minute); err != nil || c != b {
+		t.Fatalf("expected expired node %d, got %d (%v)", b, c, err)
+	}
+
+	if err = s.Release(ctx, a, "a"); err != nil {
+		t.Fatal(err)
+	}
+
+	if d, err := s.Acquire(ctx, "d", time.Minute); err != nil || d != a {
+		t.Fatalf("expected released node %d, got %d (%v)", a, d, err)
+	}
+}
+
+func TestFileLeaseStoreExhausted(t *testing.T) {
+	ctx := context.Background()
+	s, err := NewFileLeaseStore(t.TempDir())
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for i := range 63 {
+		if _, err = s.Acquire(ctx, strconv.Itoa(i), time.Minute); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if _, err = s.Acquire(ctx, "b", time.Minute); err != ErrNoFreeNode {
+		t.Fatalf("expected ErrNoFreeNode, got %v", err)
+	}
+}
+
+func TestFileLeaseStoreMaxNode(t *testing.T) {
+	ctx := context.Background()
+	s, err := NewFileLeaseStore(t.TempDir())
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	layout := Layout{NodeBits: 10, SeqBits: 11}
+	s.SetMaxNode(layout.MaxNode())
+
+	for i := range 100 {
+		if _, err = s.Acquire(ctx, strconv.Itoa(i), time.Minute); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	l, err := LeaseNode(ctx, s, time.Minute)
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	defer l.Close()
+
+	if l.Node() != 101 {
+		t.Fatalf("expected node 101, got %d", l.Node())
+	}
+
+	g, err := New(WithLayout(layout), WithLease(l))
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if id := g.ID(); layout.Node(id) != 101 {
+		t.Fatalf("expected node 101, got %d", layout.Node(id))
+	}
+
+	if _, err = New(WithLease(l)); err == nil {
+		t.Fatal("expected an error for node 101 with DefaultLayout")
+	}
+}
+
+func TestFileLeaseStoreStaleLock(t *testing.T) {
+	dir := t.TempDir()
+	s, err := NewFileLeaseStore(dir)
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// A lock abandoned by a crashed process
+	lock := filepath.Join(dir, ".lock")
+	stale := time.Now().Add(-2 * fileLeaseLockStale)
+
+	if err = os.WriteFile(lock, nil, 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	if err = os.Chtimes(lock, stale, stale); err != nil {
+		t.Fatal(err)
+	}
+
+	// Everyone takes over the stale lock at once, but only one at a time may hold it
+	var wg sync.WaitGroup
+	var holders, overlaps atomic.Int32
+
+	for range 20 {
+		wg.Add(1)
+
+		go func() {
+			defer wg.Done()
+
+			err := s.locked(context.Background(), func() error {
+				if holders.Add(1) > 1 {
+					overlaps.Add(1)
+				}
+
+				time.Sleep(time.Millisecond)
+				holders.Add(-1)
+				return nil
+			})
+
+			if err != nil {
+				t.Error(err)
+			}
+		}()
+	}
+
+	wg.Wait()
+
+	if n := overlaps.Load(); n > 0 {
+		t.Fatalf("expected the lock to be held by one at a time, overlapped %d times", n)
+	}
+
+	if _, err = os.Stat(lock); !errors.Is(err, fs.ErrNotExist) {
+		t.Fatalf("expected the lock to be released, got %v", err)
+	}
+
+	// Another process takes over the stale lock between our check and our removal
+	if err = os.WriteFile(lock, nil, 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	info, _ := os.Stat(lock)
+	os.Remove(lock)
+
+	if err = os.WriteFile(lock, nil, 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	if err = breakLock(lock, info); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err = os.Stat(lock); err != nil {
+		t.Fatalf("expected the new lock to be kept, got %v", err)
+	}
+}
+
+func TestSQLLeaseStoreQueries(t *testing.T) {
+	s := NewSQLLeaseStore(nil, "hexid_leases")
+	s.SetMaxNode(1023)
+
+	if s.maxNode != 1023 {
+		t.Fatalf("expected max node 1023, got %d", s.maxNode)
+	}
+
+	for _, tc := range []struct {
+		query string
+		args  int
+		want  []string
+	}{
+		{s.createQuery(), 0, []string{"CREATE TABLE IF NOT EXISTS hexid_leases (", "node       integer     PRIMARY KEY"}},
+		{s.acquireQuery(), 3, []string{"INSERT INTO hexid_leases AS l", "generate_series(1, $3)", "SELECT 1 FROM hexid_leases WHERE node = n", "ON CONFLICT (node)", "RETURNING node"}},
+		{s.renewQuery(), 3, []string{"INSERT INTO hexid_leases AS l", "VALUES ($1, $2,", "WHERE l.owner = excluded.owner"}},
+		{s.releaseQuery(), 2, []string{"DELETE FROM hexid_leases WHERE node = $1 AND owner = $2"}},
+	} {
+		for _, want := range tc.want {
+			if !strings.Contains(tc.query, want) {
+				t.Errorf("expected %q in:\n%s", want, tc.query)
+			}
+		}
+
+		// Every argument is used, and no others
+		for i := 1; i <= tc.args+1; i++ {
+			if used := strings.Contains(tc.query, "$"+strconv.Itoa(i)); used != (i <= tc.args) {
+				t.Errorf("expected $%d to be used = %v in:\n%s", i, i <= tc.args, tc.query)
+			}
+		}
+	}
+}
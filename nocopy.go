@@ -0,0 +1,8 @@
+package hexid
+
+// noCopy may be added to structs which must not be copied after the first use. It's
+// detected by the -copylocks checker of `go vet`.
+type noCopy struct{}
+
+func (*noCopy) Lock()   {}
+func (*noCopy) Unlock() {}
@@ -0,0 +1,162 @@
+package hexid
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"os"
+	"strconv"
+	"strings"
+)
+
+// Name of the environment variable that the global generator reads its node ID from.
+const NodeEnv = "HEXID_NODE"
+
+// A NodeResolver derives a node ID between 1 and max from the environment, where max is the
+// highest node ID of the generator's layout, e.g. 63 with DefaultLayout.
+type NodeResolver func(max uint16) (uint16, error)
+
+// Resolve the node ID from the environment variable with the provided name, e.g. NodeEnv.
+func NodeFromEnv(name string) NodeResolver {
+	return func(max uint16) (uint16, error) {
+		v, ok := os.LookupEnv(name)
+
+		if !ok {
+			return 0, fmt.Errorf("environment variable %s is not set", name)
+		}
+
+		return parseNode(v, max)
+	}
+}
+
+// Resolve the node ID from the pod ordinal of a Kubernetes StatefulSet, which is the numeric
+// suffix of the hostname (e.g. "web-0"). As node 0 is reserved for hashed IDs, the node ID is
+// the ordinal + 1, which limits the StatefulSet to 63 replicas with DefaultLayout.
+func NodeFromStatefulSet() NodeResolver {
+	return func(max uint16) (uint16, error) {
+		hostname, err := os.Hostname()
+
+		if err != nil {
+			return 0, err
+		}
+
+		return nodeFromOrdinal(hostname, max)
+	}
+}
+
+// Resolve the node ID from a hash of the hostname. Different hosts might end up with the same
+// node ID, so this should only be used when there is no better option.
+func NodeFromHostname() NodeResolver {
+	return func(max uint16) (uint16, error) {
+		hostname, err := os.Hostname()
+
+		if err != nil {
+			return 0, err
+		}
+
+		return nodeFromHash(s2b(hostname), max), nil
+	}
+}
+
+// Resolve the node ID from a hash of the first non-loopback hardware (MAC) address. Different
+// hosts might end up with the same node ID, so this should only be used when there is no better
+// option.
+func NodeFromMAC() NodeResolver {
+	return func(max uint16) (uint16, error) {
+		ifaces, err := net.Interfaces()
+
+		if err != nil {
+			return 0, err
+		}
+
+		for _, iface := range ifaces {
+			if iface.Flags&net.FlagLoopback == 0 && len(iface.HardwareAddr) > 0 {
+				return nodeFromHash(iface.HardwareAddr, max), nil
+			}
+		}
+
+		return 0, errors.New("no hardware address found")
+	}
+}
+
+// Resolve the node ID from a file that contains nothing but the node ID.
+func NodeFromFile(path string) NodeResolver {
+	return func(max uint16) (uint16, error) {
+		b, err := os.ReadFile(path)
+
+		if err != nil {
+			return 0, err
+		}
+
+		return parseNode(b2s(b), max)
+	}
+}
+
+// Resolve the node ID with the first resolver that succeeds. If all of them fail, all errors
+// are returned.
+func FirstNode(resolvers ...NodeResolver) NodeResolver {
+	return func(max uint16) (uint16, error) {
+		errs := make([]error, 0, len(resolvers))
+
+		for _, r := range resolvers {
+			node, err := r(max)
+
+			if err == nil {
+				return node, nil
+			}
+
+			errs = append(errs, err)
+		}
+
+		if len(errs) == 0 {
+			return 0, errors.New("no node resolvers")
+		}
+
+		return 0, errors.Join(errs...)
+	}
+}
+
+// Node ID of the generator, resolved from the environment once all options are applied, so that
+// it's checked against the layout set by WithLayout. Fails if the node can't be resolved.
+func WithNodeResolver(r NodeResolver) Option {
+	return func(c *config) error {
+		if r == nil {
+			return errors.New("node resolver must not be nil")
+		}
+
+		c.resolver = r
+		return nil
+	}
+}
+
+func parseNode(s string, max uint16) (uint16, error) {
+	v, err := strconv.ParseUint(strings.TrimSpace(s), 10, 16)
+
+	if err != nil || v < 1 || v > uint64(max) {
+		return 0, fmt.Errorf("invalid node %q: must be between 1 and %d", s, max)
+	}
+
+	return uint16(v), nil
+}
+
+func nodeFromOrdinal(hostname string, max uint16) (uint16, error) {
+	i := strings.LastIndexByte(hostname, '-')
+
+	if i < 0 {
+		return 0, fmt.Errorf("hostname %q has no StatefulSet ordinal", hostname)
+	}
+
+	ordinal, err := strconv.ParseUint(hostname[i+1:], 10, 16)
+
+	if err != nil || ordinal >= uint64(max) {
+		return 0, fmt.Errorf("hostname %q has no StatefulSet ordinal between 0 and %d", hostname, max-1)
+	}
+
+	return uint16(ordinal) + 1, nil
+}
+
+func nodeFromHash(b []byte, max uint16) uint16 {
+	h := newFnv64a()
+	h.Write(b)
+	return uint16(h.Sum64()%uint64(max)) + 1
+}
@@ -0,0 +1,165 @@
+package hexid
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestNodeFromEnv(t *testing.T) {
+	t.Setenv("HEXID_TEST_NODE", " 42\n")
+
+	if node, err := NodeFromEnv("HEXID_TEST_NODE")(63); err != nil || node != 42 {
+		t.Fatalf("expected node 42, got %d (%v)", node, err)
+	}
+
+	for _, v := range []string{"", "0", "64", "abc", "-1"} {
+		t.Setenv("HEXID_TEST_NODE", v)
+
+		if _, err := NodeFromEnv("HEXID_TEST_NODE")(63); err == nil {
+			t.Errorf("expected an error for %q", v)
+		}
+	}
+
+	if _, err := NodeFromEnv("HEXID_TEST_MISSING")(63); err == nil {
+		t.Error("expected an error for a missing variable")
+	}
+
+	t.Setenv("HEXID_TEST_NODE", "500")
+
+	if node, err := NodeFromEnv("HEXID_TEST_NODE")(1023); err != nil || node != 500 {
+		t.Fatalf("expected node 500, got %d (%v)", node, err)
+	}
+}
+
+func TestNodeFromOrdinal(t *testing.T) {
+	tests := []struct {
+		hostname string
+		max      uint16
+		node     uint16
+		ok       bool
+	}{
+		{"web-0", 63, 1, true},
+		{"my-app-web-62", 63, 63, true},
+		{"web-63", 63, 0, false},
+		{"web-63", 1023, 64, true},
+		{"web-1022", 1023, 1023, true},
+		{"web-1023", 1023, 0, false},
+		{"web", 63, 0, false},
+		{"web-", 63, 0, false},
+		{"web-abc", 63, 0, false},
+	}
+
+	for _, tt := range tests {
+		node, err := nodeFromOrdinal(tt.hostname, tt.max)
+
+		if (err == nil) != tt.ok || node != tt.node {
+			t.Errorf("%s: expected node %d (ok = %v), got %d (%v)", tt.hostname, tt.node, tt.ok, node, err)
+		}
+	}
+}
+
+func TestNodeFromHash(t *testing.T) {
+	for _, s := range []string{"", "a", "web-1", "web-2", "some.long.hostname.example.com"} {
+		for _, max := range []uint16{1, 63, 1023} {
+			if node := nodeFromHash([]byte(s), max); node < 1 || node > max {
+				t.Errorf("%q: node %d is out of range 1..%d", s, node, max)
+			}
+		}
+	}
+
+	if node, err := NodeFromHostname()(63); err != nil || node < 1 || node > 63 {
+		t.Errorf("expected a node between 1 and 63, got %d (%v)", node, err)
+	}
+}
+
+func TestNodeFromFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "node")
+
+	if _, err := NodeFromFile(path)(63); err == nil {
+		t.Fatal("expected an error for a missing file")
+	}
+
+	if err := os.WriteFile(path, []byte("7\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	if node, err := NodeFromFile(path)(63); err != nil || node != 7 {
+		t.Fatalf("expected node 7, got %d (%v)", node, err)
+	}
+}
+
+func TestFirstNode(t *testing.T) {
+	t.Setenv("HEXID_TEST_NODE", "9")
+
+	r := FirstNode(
+		NodeFromEnv("HEXID_TEST_MISSING"),
+		NodeFromEnv("HEXID_TEST_NODE"),
+	)
+
+	if node, err := r(63); err != nil || node != 9 {
+		t.Fatalf("expected node 9, got %d (%v)", node, err)
+	}
+
+	if _, err := FirstNode(NodeFromEnv("HEXID_TEST_MISSING"))(63); err == nil {
+		t.Fatal("expected an error")
+	}
+
+	if _, err := FirstNode()(63); err == nil {
+		t.Fatal("expected an error")
+	}
+}
+
+func TestWithNodeResolver(t *testing.T) {
+	t.Setenv("HEXID_TEST_NODE", "12")
+
+	g, err := New(WithNodeResolver(NodeFromEnv("HEXID_TEST_NODE")))
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if id := g.ID(); id.Node() != 12 {
+		t.Fatalf("expected node 12, got %d", id.Node())
+	}
+
+	if _, err = New(WithNodeResolver(NodeFromEnv("HEXID_TEST_MISSING"))); err == nil {
+		t.Fatal("expected an error")
+	}
+}
+
+func TestWithNodeResolverLayout(t *testing.T) {
+	t.Setenv("HEXID_TEST_NODE", "500")
+	layout := Layout{NodeBits: 10, SeqBits: 11}
+
+	// The node is resolved against the layout, whatever the order of the options
+	for _, opts := range [][]Option{
+		{WithLayout(layout), WithNodeResolver(NodeFromEnv("HEXID_TEST_NODE"))},
+		{WithNodeResolver(NodeFromEnv("HEXID_TEST_NODE")), WithLayout(layout)},
+	} {
+		g, err := New(opts...)
+
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if id := g.ID(); layout.Node(id) != 500 {
+			t.Fatalf("expected node 500, got %d", layout.Node(id))
+		}
+	}
+
+	if _, err := New(WithNodeResolver(NodeFromEnv("HEXID_TEST_NODE"))); err == nil {
+		t.Fatal("expected an error for node 500 with DefaultLayout")
+	}
+
+	// The last of WithNode and WithNodeResolver wins
+	g, err := New(WithLayout(layout), WithNodeResolver(NodeFromEnv("HEXID_TEST_NODE")), WithNode(3))
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if id := g.ID(); layout.Node(id) != 3 {
+		t.Fatalf("expected node 3, got %d", layout.Node(id))
+	}
+}
@@ -0,0 +1,184 @@
+package hexid
+
+import (
+	"errors"
+	"fmt"
+	"math/rand"
+	"time"
+)
+
+// An Option configures a generator.
+type Option func(*config) error
+
+// config holds the settings shared by all generators.
+type config struct {
+	rand       rand.Source
+	clock      Clock
+	lease      *NodeLeaser
+	resolver   NodeResolver
+	stats      *stats
+	persist    *persister
+	layout     Layout
+	seq        uint32
+	seqSet     bool
+	monotonic  bool
+	base       uint32
+	node       uint16
+	overflow   OverflowPolicy
+	regression RegressionPolicy
+}
+
+func newConfig(opts ...Option) (cfg config, err error) {
+	cfg = config{
+		clock: WallClock{},
+		node:  1,
+	}
+
+	for _, opt := range opts {
+		if err = opt(&cfg); err != nil {
+			return
+		}
+	}
+
+	max := cfg.Layout().MaxNode()
+
+	if cfg.resolver != nil {
+		if cfg.node, err = cfg.resolver(max); err != nil {
+			err = fmt.Errorf("failed to resolve node: %w", err)
+			return
+		}
+	}
+
+	if cfg.node > max {
+		err = fmt.Errorf("node must be between 1 and %d", max)
+		return
+	}
+
+	if end := cfg.Layout().End(); !orWallClock(cfg.clock).Now().Before(end) {
+		err = fmt.Errorf("layout ended at %s, choose a later epoch or fewer node and sequence bits", end.UTC().Format(time.RFC3339))
+		return
+	}
+
+	if !cfg.seqSet {
+		if cfg.rand != nil {
+			r := rand.New(cfg.rand)
+			cfg.seq = r.Uint32()
+			cfg.base = r.Uint32()
+		} else {
+			cfg.seq = rand.Uint32()
+			cfg.base = rand.Uint32()
+		}
+	}
+
+	if cfg.monotonic {
+		cfg.base = 0
+	}
+
+	return
+}
+
+// Node ID of the generator, between 1 and 63 with DefaultLayout. Defaults to 1.
+func WithNode(node uint16) Option {
+	return func(c *config) error {
+		if node < 1 {
+			return errors.New("node must not be 0")
+		}
+
+		c.node = node
+		c.resolver = nil
+		return nil
+	}
+}
+
+// Clock that the generator reads the time from. Defaults to WallClock.
+func WithClock(clock Clock) Option {
+	return func(c *config) error {
+		if clock == nil {
+			return errors.New("clock must not be nil")
+		}
+
+		c.clock = clock
+		return nil
+	}
+}
+
+// Sequence number that each millisecond starts at, instead of a random one.
+func WithSequence(seq uint16) Option {
+	return func(c *config) error {
+		c.seq = uint32(seq)
+		c.base = uint32(seq)
+		c.seqSet = true
+		return nil
+	}
+}
+
+// Make every ID greater than the previous one, by starting the sequence of each millisecond at 0
+// instead of a random number. Note that the hex encoding of IDs doesn't preserve their order.
+func WithMonotonic() Option {
+	return func(c *config) error {
+		c.monotonic = true
+		c.base = 0
+		return nil
+	}
+}
+
+// What happens when the sequence of the current millisecond is exhausted. Defaults to OverflowWait.
+func WithOverflowPolicy(p OverflowPolicy) Option {
+	return func(c *config) error {
+		if err := p.validate(); err != nil {
+			return err
+		}
+
+		c.overflow = p
+		return nil
+	}
+}
+
+// What happens when the clock moves backwards. Defaults to RegressionLogical.
+func WithRegressionPolicy(p RegressionPolicy) Option {
+	return func(c *config) error {
+		if err := p.validate(); err != nil {
+			return err
+		}
+
+		c.regression = p
+		return nil
+	}
+}
+
+// Source of randomness for the initial sequence. Defaults to the global source of math/rand.
+func WithRandSource(src rand.Source) Option {
+	return func(c *config) error {
+		if src == nil {
+			return errors.New("rand source must not be nil")
+		}
+
+		c.rand = src
+		return nil
+	}
+}
+
+func withNodes(node []uint8) Option {
+	return func(c *config) error {
+		if len(node) > 0 {
+			return WithNode(uint16(node[0]))(c)
+		}
+
+		return nil
+	}
+}
+
+// Current time of the clock, in nanoseconds since the epoch.
+func (c *config) now() int64 {
+	return orWallClock(c.clock).Now().UnixNano() - c.layout.Epoch.nanos()
+}
+
+// Number of sequence numbers per millisecond.
+func (c *config) limit() uint32 {
+	return c.Layout().SeqLimit()
+}
+
+func (c *config) sleep(d time.Duration) {
+	c.stats.wait()
+	orWallClock(c.clock).Sleep(d)
+}
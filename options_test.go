@@ -0,0 +1,99 @@
+package hexid
+
+import (
+	"fmt"
+	"math/rand"
+	"testing"
+	"time"
+)
+
+func ExampleNew() {
+	g, err := New(
+		WithNode(5),
+		WithClock(NewManualClock(time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC))),
+		WithSequence(1),
+	)
+
+	if err != nil {
+		panic(err)
+	}
+
+	for range 3 {
+		id := g.ID()
+		fmt.Println(id, id.Node(), id.Seq())
+	}
+
+	// Output:
+	//
+	// 75fd43fcde9eca4f 5 1
+	// e4ea529a8378149e 5 2
+	// 53d7613828515eed 5 3
+}
+
+func TestNew(t *testing.T) {
+	ts := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	c := NewManualClock(ts)
+
+	g, err := New(
+		WithNode(63),
+		WithClock(c),
+		WithOverflowPolicy(OverflowError),
+		WithRegressionPolicy(RegressionError),
+	)
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	id := g.ID()
+
+	if id.Node() != 63 {
+		t.Fatalf("expected node 63, got %d", id.Node())
+	}
+
+	if !id.Time().Equal(ts) {
+		t.Fatalf("expected %v, got %v", ts, id.Time())
+	}
+
+	if g.overflow != OverflowError || g.regression != RegressionError {
+		t.Fatalf("policies were not applied")
+	}
+}
+
+func TestNewInvalidOptions(t *testing.T) {
+	opts := []Option{
+		WithNode(0),
+		WithNode(64),
+		WithClock(nil),
+		WithOverflowPolicy(OverflowPolicy(99)),
+		WithRegressionPolicy(RegressionPolicy(99)),
+		WithRandSource(nil),
+	}
+
+	for _, opt := range opts {
+		if _, err := New(opt); err == nil {
+			t.Errorf("expected an error")
+		}
+	}
+}
+
+func TestNewRandSource(t *testing.T) {
+	a, _ := New(WithRandSource(rand.NewSource(42)))
+	b, _ := New(WithRandSource(rand.NewSource(42)))
+
+	if a.seq != b.seq || a.base != b.base {
+		t.Fatalf("expected identical sequences from identical sources")
+	}
+}
+
+func TestNewSequence(t *testing.T) {
+	ts := time.Now()
+	a, _ := NewGenerator()
+	b, _ := New(WithSequence(7))
+
+	a.seq = 7
+
+	if x, y := a.IDFromTime(ts), b.IDFromTime(ts); x != y {
+		t.Fatalf("expected Generator and AtomicGenerator to start at the same sequence: %d vs %d", x.Seq(), y.Seq())
+	}
+}
@@ -0,0 +1,306 @@
+package hexid
+
+import (
+	"errors"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+)
+
+// Causes of a ParseError, to be tested with errors.Is. ErrSignBit is returned for values with bit 63
+// set, which IDs never have, as they must fit in a Postgres BIGINT.
+var (
+	ErrInvalidLength    = errors.New("invalid length")
+	ErrInvalidCharacter = errors.New("invalid character")
+	ErrOutOfRange       = errors.New("out of range")
+	ErrSignBit          = errors.New("sign bit set")
+	ErrAmbiguous        = errors.New("ambiguous format")
+)
+
+// A ParseError is returned when an ID can't be decoded, by e.g. IDFromString, UnmarshalText,
+// UnmarshalJSON and Scan. Its cause is one of ErrInvalidLength, ErrInvalidCharacter,
+// ErrOutOfRange, ErrSignBit or ErrChecksum, or ErrAmbiguous from ParseID.
+type ParseError struct {
+	Input  string // The input that failed to decode
+	Offset int    // Byte offset of the offending character in Input, or -1 if there's none
+	Format string // Expected format, e.g. "hex", "checked base58" or "usr_hex"
+	Err    error  // Cause of the error
+}
+
+func (e *ParseError) Error() string {
+	var b strings.Builder
+	b.WriteString("invalid ID ")
+	b.WriteString(strconv.Quote(e.Input))
+	b.WriteString(": ")
+	b.WriteString(e.Err.Error())
+
+	if e.Offset >= 0 && e.Offset < len(e.Input) {
+		if e.Err == ErrInvalidCharacter {
+			b.WriteByte(' ')
+			r, _ := utf8.DecodeRuneInString(e.Input[e.Offset:])
+			b.WriteString(strconv.QuoteRune(r))
+		}
+
+		b.WriteString(" at offset ")
+		b.WriteString(strconv.Itoa(e.Offset))
+	}
+
+	if e.Format != "" {
+		b.WriteString(", expected ")
+		b.WriteString(e.Format)
+	}
+
+	return b.String()
+}
+
+func (e *ParseError) Unwrap() error {
+	return e.Err
+}
+
+// Decodes an ID from its text encoding like IDFromString, but without rejecting values with the
+// sign bit set, which can't have been generated. Use it for IDs that were stored before decoding
+// was strict, and check them with Valid where it matters.
+func ParseLenient(str string) (ID, error) {
+	c := getCoder()
+	v, err := c.decodeLenient(str, c.enc, c.checked)
+	return ID(v), err
+}
+
+// Parses a decimal ID, as in JSON numbers.
+func parseDecimal(str string) (ID, error) {
+	v, err := strconv.ParseUint(str, 10, 64)
+
+	if err == nil {
+		if v>>63 != 0 {
+			return 0, signBitError(str, "decimal")
+		}
+
+		return ID(v), nil
+	}
+
+	pe := &ParseError{Input: str, Offset: -1, Format: "decimal", Err: ErrInvalidLength}
+
+	if errors.Is(err, strconv.ErrRange) {
+		pe.Err = ErrOutOfRange
+	} else if i := strings.IndexFunc(str, func(r rune) bool { return r < '0' || r > '9' }); i >= 0 {
+		pe.Offset, pe.Err = i, ErrInvalidCharacter
+	}
+
+	return 0, pe
+}
+
+func signBitError(input, format string) error {
+	return &ParseError{Input: input, Offset: -1, Format: format, Err: ErrSignBit}
+}
+
+// A Format is a representation of IDs, or a set of them, that ParseID accepts.
+type Format uint16
+
+const (
+	FormatText     Format = 1 << iota // Text encoding of the coder set by SetCoder, as by String
+	FormatChecked                     // Text encoding with a check character, as by AppendTextChecked
+	FormatBase32                      // As by AppendBase32
+	FormatBase58                      // As by AppendBase58
+	FormatBase62                      // As by AppendBase62
+	FormatSortable                    // As by AppendSortable
+	FormatDecimal                     // Raw numeric value in decimal, e.g. a BIGINT or a JSON number
+	FormatJSON                        // Quoted text encoding, or null for the zero ID, as by MarshalJSON
+	FormatBinary                      // 8 big-endian bytes, as by Bytes
+
+	// Formats that ParseID accepts unless restricted with WithFormats. Base32, base58, base62 and
+	// sortable encodings are left out, as base32 and sortable encodings have the same length and
+	// alphabet, and so do base58 and base62 encodings, for the most part. Binary is left out as
+	// well, as any 8 bytes decode, e.g. an 8-character path segment.
+	DefaultFormats = FormatText | FormatChecked | FormatDecimal | FormatJSON
+
+	// All formats. Accepting both of base32 and sortable, or base58 and base62, makes many
+	// inputs ambiguous, and so does accepting binary along with decimal for 8-digit input.
+	AnyFormat = DefaultFormats | FormatBase32 | FormatBase58 | FormatBase62 | FormatSortable | FormatBinary
+)
+
+var formatNames = [...]string{"text", "checked", "base32", "base58", "base62", "sortable", "decimal", "JSON", "binary"}
+
+func (f Format) String() string {
+	var b strings.Builder
+
+	for i, name := range formatNames {
+		if f&(1<<i) != 0 {
+			if b.Len() > 0 {
+				b.WriteByte('|')
+			}
+
+			b.WriteString(name)
+		}
+	}
+
+	if b.Len() == 0 {
+		return "Format(" + strconv.Itoa(int(f)) + ")"
+	}
+
+	return b.String()
+}
+
+// A ParseOption configures ParseID.
+type ParseOption func(*parseConfig)
+
+type parseConfig struct {
+	formats Format
+}
+
+// Formats that ParseID accepts. Defaults to DefaultFormats.
+func WithFormats(f Format) ParseOption {
+	return func(c *parseConfig) {
+		c.formats = f
+	}
+}
+
+// Parses an ID in any of the accepted formats, which defaults to DefaultFormats, and reports which
+// one it detected. The input is decoded in every accepted format that it could be in, and if more
+// than one succeeds with different IDs, it fails with ErrAmbiguous rather than guessing. The
+// exception is input that is valid in the text encoding, with or without a check character, which
+// is never read as decimal, as String emits all digits now and then. Restrict the accepted formats
+// with WithFormats(FormatDecimal) to read it as decimal. Decoding is strict, as by IDFromString.
+//
+// On failure, the error is the ParseError of the first format that the input could have been, or
+// ErrInvalidLength if none.
+func ParseID(s string, opts ...ParseOption) (ID, Format, error) {
+	cfg := parseConfig{formats: DefaultFormats}
+
+	for _, opt := range opts {
+		opt(&cfg)
+	}
+
+	c := getCoder()
+
+	var (
+		id             ID
+		found, matched Format
+		ambiguous      bool
+		err            error
+	)
+
+	for i := range formatNames {
+		f := Format(1 << i)
+
+		if cfg.formats&f == 0 || (f == FormatDecimal && matched&(FormatText|FormatChecked) != 0) {
+			continue
+		}
+
+		v, ok, e := parseFormat(c, s, f)
+
+		if !ok {
+			continue
+		}
+
+		if e != nil {
+			if err == nil {
+				err = e
+			}
+
+			continue
+		}
+
+		if found == 0 {
+			id, found = v, f
+		} else if v != id {
+			ambiguous = true
+		}
+
+		matched |= f
+	}
+
+	if ambiguous {
+		return 0, 0, &ParseError{Input: s, Offset: -1, Format: matched.String(), Err: ErrAmbiguous}
+	}
+
+	if found != 0 {
+		return id, found, nil
+	}
+
+	if err == nil {
+		err = &ParseError{Input: s, Offset: -1, Format: cfg.formats.String(), Err: ErrInvalidLength}
+	}
+
+	return 0, 0, err
+}
+
+// Parses an ID in a single format. Reports false if the input has the wrong length or shape for
+// the format.
+func parseFormat(c *Coder, s string, f Format) (ID, bool, error) {
+	switch f {
+
+	case FormatText:
+		return parseEncoding(c, s, c.enc, c.checked)
+
+	case FormatChecked:
+		return parseEncoding(c, s, c.enc, true)
+
+	case FormatBase32:
+		return parseEncoding(c, s, Base32, c.checked)
+
+	case FormatBase58:
+		return parseEncoding(c, s, Base58, c.checked)
+
+	case FormatBase62:
+		return parseEncoding(c, s, Base62, c.checked)
+
+	case FormatSortable:
+		return parseEncoding(sortableBase32, s, Base32, false)
+
+	case FormatDecimal:
+		if len(s) == 0 || len(s) > 20 || s[0] < '0' || s[0] > '9' {
+			return 0, false, nil
+		}
+
+		id, err := parseDecimal(s)
+		return id, true, err
+
+	case FormatJSON:
+		if s == "null" {
+			return 0, true, nil
+		}
+
+		if len(s) < 2 || s[0] != '"' || s[len(s)-1] != '"' {
+			return 0, false, nil
+		}
+
+		id, err := c.Decode(s[1 : len(s)-1])
+
+		if pe, ok := err.(*ParseError); ok {
+			pe.Input = s
+			pe.Format = "JSON " + pe.Format
+
+			if pe.Offset >= 0 {
+				pe.Offset++
+			}
+		}
+
+		return id, true, err
+
+	case FormatBinary:
+		if len(s) != 8 {
+			return 0, false, nil
+		}
+
+		id, err := IDFromBinary(s2b(s))
+		return id, true, err
+	}
+
+	return 0, false, nil
+}
+
+// Parses an ID in an encoding of the coder, like parseFormat.
+func parseEncoding(c *Coder, s string, e Encoding, checked bool) (ID, bool, error) {
+	n := e.Len()
+
+	if checked {
+		n++
+	}
+
+	if len(s) != n {
+		return 0, false, nil
+	}
+
+	id, err := c.decodeWith(s, e, checked)
+	return id, true, err
+}
@@ -0,0 +1,315 @@
+package hexid
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+func ExampleParseError() {
+	_, err := IDFromString("bee99827fa22a2g1")
+
+	var pe *ParseError
+
+	if errors.As(err, &pe) {
+		fmt.Println(pe.Offset, errors.Is(err, ErrInvalidCharacter))
+	}
+
+	fmt.Println(err)
+
+	// Output:
+	// 14 true
+	// invalid ID "bee99827fa22a2g1": invalid character 'g' at offset 14, expected hex
+}
+
+func TestParseError(t *testing.T) {
+	base58, _ := DefaultCoder.WithEncoding(Base58)
+
+	for _, tc := range []struct {
+		parse  func(string) (ID, error)
+		input  string
+		err    error
+		offset int
+		format string
+	}{
+		{IDFromString, "", ErrInvalidLength, -1, "hex"},
+		{IDFromString, "bee99827fa22a2c1f", ErrInvalidLength, -1, "hex"},
+		{IDFromString, "bee99827fa22a2cx", ErrInvalidCharacter, 15, "hex"},
+		{IDFromString, "BEE99827FA22A2C1", nil, 0, ""},
+		{IDFromBase32, "G000000000000", ErrOutOfRange, 0, "base32"},
+		{IDFromBase32, "0000000000U00", ErrInvalidCharacter, 10, "base32"},
+		{base58.Decode, "zzzzzzzzzzz", ErrOutOfRange, 10, "base58"},
+		{base58.Decode, "1111l111111", ErrInvalidCharacter, 4, "base58"},
+		{IDFromBase62, "000000000-0", ErrInvalidCharacter, 9, "base62"},
+		{ParseChecked, "bee99827fa22a2c1", ErrInvalidLength, -1, "checked hex"},
+		{ParseChecked, "bee99827fa22a2c1x", ErrInvalidCharacter, 16, "checked hex"},
+		{ParseChecked, "bee99827fa22a2d17", ErrChecksum, -1, "checked hex"},
+	} {
+		_, err := tc.parse(tc.input)
+
+		if tc.err == nil {
+			if err != nil {
+				t.Errorf("expected %q to be valid, got %v", tc.input, err)
+			}
+
+			continue
+		}
+
+		var pe *ParseError
+
+		if !errors.As(err, &pe) || !errors.Is(err, tc.err) {
+			t.Errorf("expected %v for %q, got %v", tc.err, tc.input, err)
+			continue
+		}
+
+		if pe.Input != tc.input || pe.Offset != tc.offset || pe.Format != tc.format {
+			t.Errorf("expected %q at %d in %s, got %q at %d in %s", tc.input, tc.offset, tc.format, pe.Input, pe.Offset, pe.Format)
+		}
+	}
+}
+
+func TestParseErrorJSON(t *testing.T) {
+	var id ID
+
+	for _, tc := range []struct {
+		input  string
+		err    error
+		offset int
+	}{
+		{`"bee99827fa22a2cx"`, ErrInvalidCharacter, 15},
+		{`12345x`, ErrInvalidCharacter, 5},
+		{`-1`, ErrInvalidCharacter, 0},
+		{`18446744073709551616`, ErrOutOfRange, -1},
+		{`true`, ErrInvalidCharacter, 0},
+	} {
+		var pe *ParseError
+		err := id.UnmarshalJSON([]byte(tc.input))
+
+		if !errors.As(err, &pe) || !errors.Is(err, tc.err) || pe.Offset != tc.offset {
+			t.Errorf("expected %v at %d for %s, got %v", tc.err, tc.offset, tc.input, err)
+		}
+	}
+
+	if err := id.Scan("bee99827fa22a2c"); !errors.Is(err, ErrInvalidLength) {
+		t.Errorf("expected ErrInvalidLength, got %v", err)
+	}
+
+	if err := id.UnmarshalText([]byte("bee99827fa22a2cx")); !errors.Is(err, ErrInvalidCharacter) {
+		t.Errorf("expected ErrInvalidCharacter, got %v", err)
+	}
+}
+
+func TestParseErrorTyped(t *testing.T) {
+	str := GenerateTyped[user]().String()
+
+	for _, tc := range []struct {
+		input  string
+		err    error
+		offset int
+	}{
+		{"ord_" + str[4:], ErrInvalidCharacter, 0},
+		{"usrx" + str[4:], ErrInvalidCharacter, 3},
+		{"us", ErrInvalidLength, -1},
+		{"usr_", ErrInvalidLength, -1},
+		{str[:19] + "x", ErrInvalidCharacter, 19},
+	} {
+		var pe *ParseError
+		_, err := ParseTypedID[user](tc.input)
+
+		if !errors.As(err, &pe) || !errors.Is(err, tc.err) || pe.Offset != tc.offset || pe.Input != tc.input || pe.Format != "usr_hex" {
+			t.Errorf("expected %v at %d for %q, got %v", tc.err, tc.offset, tc.input, err)
+		}
+	}
+}
+
+func TestParseSignBit(t *testing.T) {
+	const v = 1<<63 | 123
+	str := DefaultCoder.Encode(ID(v))
+
+	if _, err := IDFromString(str); !errors.Is(err, ErrSignBit) {
+		t.Fatalf("expected ErrSignBit for %q, got %v", str, err)
+	}
+
+	if id, err := ParseLenient(str); err != nil || id != v {
+		t.Fatalf("expected %d, got %d (%v)", uint64(v), id, err)
+	}
+
+	if _, err := ParseTypedID[user]("usr_" + str); !errors.Is(err, ErrSignBit) {
+		t.Fatalf("expected ErrSignBit, got %v", err)
+	}
+
+	var id ID
+
+	for _, src := range []any{int64(-1), uint64(v), []byte{0x80, 0, 0, 0, 0, 0, 0, 123}, str} {
+		if err := id.Scan(src); !errors.Is(err, ErrSignBit) {
+			t.Errorf("expected ErrSignBit when scanning %v, got %v", src, err)
+		}
+	}
+
+	if err := id.UnmarshalJSON([]byte("9223372036854775931")); !errors.Is(err, ErrSignBit) {
+		t.Errorf("expected ErrSignBit, got %v", err)
+	}
+
+	var typed TypedID[user]
+
+	if err := typed.UnmarshalJSON([]byte("9223372036854775931")); !errors.Is(err, ErrSignBit) {
+		t.Errorf("expected ErrSignBit, got %v", err)
+	}
+
+	if err := typed.Scan([]byte{0x80, 0, 0, 0, 0, 0, 0, 123}); !errors.Is(err, ErrSignBit) {
+		t.Errorf("expected ErrSignBit, got %v", err)
+	}
+
+	if err := id.Scan(int64(1<<63 - 1)); err != nil || id != 1<<63-1 {
+		t.Errorf("expected the largest ID to be scanned, got %d (%v)", id, err)
+	}
+}
+
+func ExampleParseID() {
+	for _, s := range []string{"bee99827fa22a2c1", "BEE99827FA22A2C1", "1311768467294899695", `"bee99827fa22a2c1"`} {
+		id, f, _ := ParseID(s)
+		fmt.Printf("%x %s\n", uint64(id), f)
+	}
+
+	// Output:
+	// 1234567890abcdef text
+	// 1234567890abcdef text
+	// 1234567890abcdef decimal
+	// 1234567890abcdef JSON
+}
+
+func TestParseID(t *testing.T) {
+	id := ID(0x1234567890abcdef)
+	checked, _ := id.AppendTextChecked(nil)
+
+	for _, tc := range []struct {
+		input   string
+		formats Format
+		want    Format
+	}{
+		{id.String(), DefaultFormats, FormatText},
+		{strings.ToUpper(id.String()), AnyFormat, FormatText},
+		{string(checked), AnyFormat, FormatChecked},
+		{string(id.AppendBase32(nil)), FormatBase32, FormatBase32},
+		{string(id.AppendBase58(nil)), FormatBase58, FormatBase58},
+		{string(id.AppendBase62(nil)), FormatBase62, FormatBase62},
+		{id.Sortable(), FormatSortable | FormatText, FormatSortable},
+		{strconv.FormatUint(uint64(id), 10), AnyFormat, FormatDecimal},
+		{`"` + id.String() + `"`, AnyFormat, FormatJSON},
+		{string(id.Bytes()), AnyFormat, FormatBinary},
+	} {
+		got, f, err := ParseID(tc.input, WithFormats(tc.formats))
+
+		if err != nil || got != id || f != tc.want {
+			t.Errorf("expected %d in %s from %q, got %d in %s (%v)", id, tc.want, tc.input, got, f, err)
+		}
+	}
+
+	if got, f, err := ParseID("null"); err != nil || got != 0 || f != FormatJSON {
+		t.Errorf("expected the zero ID from null, got %d in %s (%v)", got, f, err)
+	}
+}
+
+func TestParseIDAmbiguous(t *testing.T) {
+	var ambiguous int
+
+	for range 200 {
+		id := Generate()
+
+		for _, input := range []string{string(id.AppendBase62(nil)), id.Sortable()} {
+
+			// Not accepted by default
+			if got, f, err := ParseID(input); err == nil {
+				t.Fatalf("expected %q to fail, got %d in %s", input, got, f)
+			}
+
+			// Either decoded right, or ambiguous
+			got, f, err := ParseID(input, WithFormats(AnyFormat))
+
+			if errors.Is(err, ErrAmbiguous) {
+				ambiguous++
+			} else if err != nil || got != id || (f != FormatBase62 && f != FormatSortable) {
+				t.Fatalf("expected %d from %q, got %d in %s (%v)", id, input, got, f, err)
+			}
+		}
+	}
+
+	if ambiguous == 0 {
+		t.Fatal("expected some inputs to be ambiguous")
+	}
+}
+
+func TestParseIDDigits(t *testing.T) {
+	// Hex that is all digits is a valid decimal as well, but is read as text
+	const s = "8465648057401291"
+	want, _ := IDFromString(s)
+
+	if id, f, err := ParseID(s); err != nil || id != want || f != FormatText {
+		t.Fatalf("expected %d in text, got %d in %s (%v)", want, id, f, err)
+	}
+
+	if id, f, err := ParseID(s, WithFormats(FormatDecimal)); err != nil || id != 8465648057401291 || f != FormatDecimal {
+		t.Fatalf("expected %s in decimal, got %d in %s (%v)", s, id, f, err)
+	}
+
+	checked, _ := want.AppendTextChecked(nil)
+
+	if id, f, err := ParseID(string(checked)); err != nil || id != want || f != FormatChecked {
+		t.Fatalf("expected %d in checked, got %d in %s (%v)", want, id, f, err)
+	}
+}
+
+func TestParseIDGenerated(t *testing.T) {
+	for range 200_000 {
+		id := Generate()
+
+		if got, _, err := ParseID(id.String()); err != nil || got != id {
+			t.Fatalf("expected %d from %q, got %d (%v)", id, id.String(), got, err)
+		}
+	}
+}
+
+func TestParseIDBinary(t *testing.T) {
+	if id, f, err := ParseID("garbage!"); err == nil {
+		t.Fatalf("expected an error, got %d in %s", id, f)
+	}
+
+	if id, f, err := ParseID("12345678"); err != nil || id != 12345678 || f != FormatDecimal {
+		t.Fatalf("expected 12345678 in decimal, got %d in %s (%v)", id, f, err)
+	}
+
+	id := Generate()
+
+	if got, f, err := ParseID(string(id.Bytes()), WithFormats(DefaultFormats|FormatBinary)); err != nil || got != id || f != FormatBinary {
+		t.Fatalf("expected %d in binary, got %d in %s (%v)", id, got, f, err)
+	}
+}
+
+func TestParseIDInvalid(t *testing.T) {
+	id := Generate()
+
+	for _, tc := range []struct {
+		input   string
+		formats Format
+		err     error
+		format  string
+	}{
+		{"", AnyFormat, ErrInvalidLength, AnyFormat.String()},
+		{"abc", FormatDecimal | FormatBinary, ErrInvalidLength, "decimal|binary"},
+		{"123abc", FormatDecimal | FormatBinary, ErrInvalidCharacter, "decimal"},
+		{id.String()[:15] + "x", AnyFormat, ErrInvalidCharacter, "hex"},
+		{`"` + id.String()[:15] + `x"`, AnyFormat, ErrInvalidCharacter, "JSON hex"},
+		{strconv.FormatUint(uint64(id), 10), FormatText, ErrInvalidLength, "text"},
+		{strconv.FormatUint(1<<63, 10), AnyFormat, ErrSignBit, "decimal"},
+		{DefaultCoder.Encode(1<<63 | id), FormatText, ErrSignBit, "hex"},
+	} {
+		var pe *ParseError
+		_, _, err := ParseID(tc.input, WithFormats(tc.formats))
+
+		if !errors.As(err, &pe) || !errors.Is(err, tc.err) || pe.Format != tc.format {
+			t.Errorf("expected %v in %s for %q, got %v", tc.err, tc.format, tc.input, err)
+		}
+	}
+}
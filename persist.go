@@ -0,0 +1,167 @@
+package hexid
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// How far ahead of the generated IDs the persisted high-water mark is kept.
+const stateAhead = time.Second
+
+// A StateStore persists the high-water mark of a generator, i.e. a time that none of its IDs have
+// reached yet, so that a restarted generator never issues IDs from before it. See WithStateStore.
+type StateStore interface {
+
+	// Load the persisted mark. Returns the zero time if there is none.
+	Load() (time.Time, error)
+
+	// Save the mark durably. Must not return before the mark survives a crash.
+	Save(mark time.Time) error
+}
+
+// Persist the high-water mark of the generator in the store, and resume from the persisted mark on
+// creation. The mark is kept ahead of the generated IDs, and is saved about once per second.
+//
+// After a restart the generator treats the clock as being behind the mark, and its regression
+// policy decides what happens: RegressionLogical issues IDs from the mark onwards, RegressionWait
+// waits for the clock to pass the mark, and RegressionError fails until it does. Fails if the mark
+// can't be loaded, and TryID fails if it can't be saved.
+func WithStateStore(store StateStore) Option {
+	return func(c *config) error {
+		if store == nil {
+			return errors.New("state store must not be nil")
+		}
+
+		c.persist = &persister{store: store}
+		return nil
+	}
+}
+
+// persister keeps the high-water mark of a generator in a StateStore.
+type persister struct {
+	mark  int64 // Milliseconds since the epoch that IDs may be issued up until, exclusive
+	mu    sync.Mutex
+	store StateStore
+}
+
+// Loads the persisted mark in milliseconds since the epoch, or -1 if there is none.
+func (p *persister) load(e Epoch) (int64, error) {
+	t, err := p.store.Load()
+
+	if err != nil {
+		return 0, fmt.Errorf("failed to load generator state: %w", err)
+	}
+
+	if t.IsZero() {
+		return -1, nil
+	}
+
+	ms := (t.UnixNano() - e.nanos()) / 1e6
+	atomic.StoreInt64(&p.mark, ms)
+	return ms, nil
+}
+
+// Makes sure that the persisted mark is beyond ms before any ID of it is issued.
+func (p *persister) reach(ms int64, e Epoch) error {
+	if ms < atomic.LoadInt64(&p.mark) {
+		return nil
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if ms < atomic.LoadInt64(&p.mark) {
+		return nil
+	}
+
+	mark := ms + stateAhead.Milliseconds()
+
+	if err := p.store.Save(time.UnixMilli(mark).Add(time.Duration(e.nanos()))); err != nil {
+		return fmt.Errorf("failed to save generator state: %w", err)
+	}
+
+	atomic.StoreInt64(&p.mark, mark)
+	return nil
+}
+
+// Resumes from the persisted mark, if any, so that no ID before it is issued.
+func (c *config) restore(m *clockMonitor, states ...*uint64) error {
+	if c.persist == nil {
+		return nil
+	}
+
+	ms, err := c.persist.load(c.Epoch())
+
+	if err != nil || ms < 0 {
+		return err
+	}
+
+	for _, s := range states {
+		atomic.StoreUint64(s, uint64(newState(ms, c.limit())))
+	}
+
+	atomic.StoreInt64(&m.wall, ms)
+	return nil
+}
+
+var _ StateStore = (*ReadWriterStateStore)(nil)
+
+// A StateStore on top of any io.ReadWriter, e.g. a file or a network connection. The mark is
+// written as 8 bytes of big-endian unix milliseconds. When the underlying value is an io.Seeker, the
+// mark is overwritten in place, and otherwise appended. When it has a Sync method, e.g. *os.File,
+// the mark is synced after each save.
+type ReadWriterStateStore struct {
+	rw io.ReadWriter
+}
+
+// Create a state store on top of rw.
+func NewReadWriterStateStore(rw io.ReadWriter) *ReadWriterStateStore {
+	return &ReadWriterStateStore{rw: rw}
+}
+
+// Load implements StateStore by reading marks until EOF, and returning the last one.
+func (s *ReadWriterStateStore) Load() (mark time.Time, err error) {
+	if seeker, ok := s.rw.(io.Seeker); ok {
+		if _, err = seeker.Seek(0, io.SeekStart); err != nil {
+			return
+		}
+	}
+
+	var buf [8]byte
+
+	for {
+		if _, err = io.ReadFull(s.rw, buf[:]); err != nil {
+			if err == io.EOF {
+				err = nil
+			}
+
+			return
+		}
+
+		mark = time.UnixMilli(int64(binary.BigEndian.Uint64(buf[:])))
+	}
+}
+
+// Save implements StateStore.
+func (s *ReadWriterStateStore) Save(mark time.Time) (err error) {
+	if seeker, ok := s.rw.(io.Seeker); ok {
+		if _, err = seeker.Seek(0, io.SeekStart); err != nil {
+			return
+		}
+	}
+
+	if _, err = s.rw.Write(binary.BigEndian.AppendUint64(nil, uint64(mark.UnixMilli()))); err != nil {
+		return
+	}
+
+	if syncer, ok := s.rw.(interface{ Sync() error }); ok {
+		err = syncer.Sync()
+	}
+
+	return
+}
@@ -0,0 +1,83 @@
+package hexid
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+)
+
+var _ StateStore = (*FileStateStore)(nil)
+
+// A StateStore that keeps the mark as unix milliseconds in a text file. Each save is written to a
+// temporary file that is synced and then renamed over the previous one, so that a crash never
+// leaves a partial mark behind.
+type FileStateStore struct {
+	path string
+}
+
+// Create a file state store at path. The directory must exist.
+func NewFileStateStore(path string) *FileStateStore {
+	return &FileStateStore{path: path}
+}
+
+// Load implements StateStore.
+func (s *FileStateStore) Load() (time.Time, error) {
+	b, err := os.ReadFile(s.path)
+
+	if err != nil {
+		if errors.Is(err, fs.ErrNotExist) {
+			err = nil
+		}
+
+		return time.Time{}, err
+	}
+
+	ms, err := strconv.ParseInt(strings.TrimSpace(b2s(b)), 10, 64)
+
+	if err != nil {
+		return time.Time{}, fmt.Errorf("corrupt state file: %s", s.path)
+	}
+
+	return time.UnixMilli(ms), nil
+}
+
+// Save implements StateStore.
+func (s *FileStateStore) Save(mark time.Time) error {
+	tmp := s.path + ".tmp"
+	f, err := os.OpenFile(tmp, os.O_CREATE|os.O_TRUNC|os.O_WRONLY, 0o644)
+
+	if err != nil {
+		return err
+	}
+
+	if _, err = fmt.Fprintf(f, "%d\n", mark.UnixMilli()); err == nil {
+		err = f.Sync()
+	}
+
+	if cerr := f.Close(); err == nil {
+		err = cerr
+	}
+
+	if err != nil {
+		return err
+	}
+
+	if err = os.Rename(tmp, s.path); err != nil {
+		return err
+	}
+
+	// Sync the directory, so that the rename itself survives a crash
+	dir, err := os.Open(filepath.Dir(s.path))
+
+	if err != nil {
+		return err
+	}
+
+	defer dir.Close()
+	return dir.Sync()
+}
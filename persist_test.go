@@ -0,0 +1,123 @@
+package hexid
+
+import (
+	"bytes"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestStateStoreRestart(t *testing.T) {
+	store := NewFileStateStore(filepath.Join(t.TempDir(), "hexid.state"))
+	ts := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	g1, err := New(WithStateStore(store), WithClock(NewManualClock(ts)))
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	last := g1.ID()
+
+	// Restart with a clock that has moved backwards
+	g2, err := New(WithStateStore(store), WithClock(NewManualClock(ts.Add(-time.Minute))))
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if id := g2.ID(); !id.Time().After(last.Time()) {
+		t.Fatalf("expected %v to be after %v", id.Time(), last.Time())
+	}
+
+	g3, _ := New(
+		WithStateStore(store),
+		WithClock(NewManualClock(ts.Add(-time.Minute))),
+		WithRegressionPolicy(RegressionError),
+	)
+
+	if _, err := g3.TryID(); !errors.Is(err, ErrClockMovedBackwards) {
+		t.Fatalf("expected ErrClockMovedBackwards, got %v", err)
+	}
+
+	mark, err := store.Load()
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !mark.After(last.Time()) {
+		t.Fatalf("expected the mark %v to be after %v", mark, last.Time())
+	}
+}
+
+func TestStateStoreEpoch(t *testing.T) {
+	var buf bytes.Buffer
+
+	store := NewReadWriterStateStore(&buf)
+	epoch := NewEpoch(time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC))
+	ts := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	g1, _ := New(WithStateStore(store), WithEpoch(epoch), WithClock(NewManualClock(ts)))
+	last := epoch.Time(g1.ID())
+
+	g2, err := New(WithStateStore(store), WithClock(NewManualClock(ts)))
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if id := g2.ID(); !id.Time().After(last) {
+		t.Fatalf("expected %v to be after %v", id.Time(), last)
+	}
+
+	// Loading consumed the first mark, so only the one saved by g2 is left
+	if buf.Len() != 8 {
+		t.Fatalf("expected 1 mark, got %d bytes", buf.Len())
+	}
+}
+
+func TestReadWriterStateStoreFile(t *testing.T) {
+	f, err := os.Create(filepath.Join(t.TempDir(), "hexid.state"))
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	defer f.Close()
+
+	store := NewReadWriterStateStore(f)
+
+	if mark, err := store.Load(); err != nil || !mark.IsZero() {
+		t.Fatalf("expected no mark, got %v (%v)", mark, err)
+	}
+
+	for _, ms := range []int64{1000, 2000} {
+		if err := store.Save(time.UnixMilli(ms)); err != nil {
+			t.Fatal(err)
+		}
+
+		if mark, err := store.Load(); err != nil || mark.UnixMilli() != ms {
+			t.Fatalf("expected mark %d, got %v (%v)", ms, mark, err)
+		}
+	}
+
+	if info, _ := f.Stat(); info.Size() != 8 {
+		t.Fatalf("expected the mark to be overwritten, got %d bytes", info.Size())
+	}
+}
+
+type failingStateStore struct{}
+
+func (failingStateStore) Load() (time.Time, error) { return time.Time{}, nil }
+func (failingStateStore) Save(time.Time) error     { return errors.New("disk full") }
+
+func TestStateStoreSaveError(t *testing.T) {
+	g, _ := New(WithStateStore(failingStateStore{}))
+
+	if _, err := g.TryID(); err == nil {
+		t.Fatal("expected an error when the mark can't be saved")
+	}
+}
@@ -0,0 +1,52 @@
+package hexid
+
+import (
+	"errors"
+	"fmt"
+)
+
+// ErrSequenceExhausted is returned by TryID when all sequence numbers of the current
+// millisecond have been issued and the generator uses OverflowError.
+var ErrSequenceExhausted = errors.New("sequence exhausted for the current millisecond")
+
+// OverflowPolicy decides what a generator does when more than 2^15 (32,768) IDs are
+// requested within the same millisecond.
+type OverflowPolicy uint8
+
+const (
+	OverflowWait   OverflowPolicy = iota // Wait until the next millisecond (default)
+	OverflowBorrow                       // Borrow the next millisecond before the clock reaches it
+	OverflowError                        // Fail with ErrSequenceExhausted
+)
+
+func (p OverflowPolicy) validate() error {
+	switch p {
+	case OverflowWait, OverflowBorrow, OverflowError:
+		return nil
+	}
+
+	return fmt.Errorf("invalid OverflowPolicy: %d", p)
+}
+
+// ErrClockMovedBackwards is returned by TryID when the clock is behind the highest time
+// previously observed by the generator, and the generator uses RegressionError.
+var ErrClockMovedBackwards = errors.New("clock moved backwards")
+
+// RegressionPolicy decides what a generator does when the clock moves backwards, e.g. after an
+// NTP adjustment. IDs are unique regardless of policy, as a generator never reuses a millisecond.
+type RegressionPolicy uint8
+
+const (
+	RegressionLogical RegressionPolicy = iota // Keep issuing from the last seen millisecond (default)
+	RegressionWait                            // Wait until the clock catches up
+	RegressionError                           // Fail with ErrClockMovedBackwards
+)
+
+func (p RegressionPolicy) validate() error {
+	switch p {
+	case RegressionLogical, RegressionWait, RegressionError:
+		return nil
+	}
+
+	return fmt.Errorf("invalid RegressionPolicy: %d", p)
+}
@@ -0,0 +1,45 @@
+package hexid
+
+import "time"
+
+// MinIDAt returns the smallest ID that can be generated within the millisecond of t, e.g. as the
+// lower bound of a range scan on the primary key. Note that hashed IDs might fall into any range.
+func MinIDAt(t time.Time) ID {
+	return DefaultLayout.MinIDAt(t)
+}
+
+// MaxIDAt returns the largest ID that can be generated within the millisecond of t, e.g. as the
+// upper bound of a range scan on the primary key.
+func MaxIDAt(t time.Time) ID {
+	return DefaultLayout.MaxIDAt(t)
+}
+
+// RangeForTimes returns the inclusive range of IDs that can be generated from the millisecond of
+// from up until and including the millisecond of to, i.e. `WHERE id BETWEEN min AND max`.
+func RangeForTimes(from, to time.Time) (min, max ID) {
+	return DefaultLayout.RangeForTimes(from, to)
+}
+
+// MinIDAt returns the smallest ID of the layout within the millisecond of t. Times outside of the
+// layout's range are clamped to it.
+func (l Layout) MinIDAt(t time.Time) ID {
+	return l.fromMilli(l.clampMilli(t), 0, 0)
+}
+
+// MaxIDAt returns the largest ID of the layout within the millisecond of t. Times outside of the
+// layout's range are clamped to it.
+func (l Layout) MaxIDAt(t time.Time) ID {
+	return l.fromMilli(l.clampMilli(t), l.MaxNode(), l.SeqLimit()-1)
+}
+
+// RangeForTimes returns the inclusive range of IDs of the layout from the millisecond of from up
+// until and including the millisecond of to.
+func (l Layout) RangeForTimes(from, to time.Time) (min, max ID) {
+	return l.MinIDAt(from), l.MaxIDAt(to)
+}
+
+// Milliseconds of t since the epoch, clamped to what fits in the layout.
+func (l Layout) clampMilli(t time.Time) int64 {
+	ms := t.UnixMilli() - int64(l.Epoch)*1000
+	return min(max(ms, 0), int64(1)<<l.secBits()*1000-1)
+}
@@ -0,0 +1,63 @@
+package hexid
+
+import (
+	"fmt"
+	"testing"
+	"time"
+)
+
+func ExampleRangeForTimes() {
+	from := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	to := time.Date(2025, 1, 31, 23, 59, 59, 999_000_000, time.UTC)
+
+	min, max := RangeForTimes(from, to)
+	fmt.Println(min.Int64(), max.Int64())
+
+	// Output: 3727365034003660800 3733116854156132351
+}
+
+func TestRangeForTimes(t *testing.T) {
+	ts := time.Date(2025, 6, 1, 12, 30, 0, 456_789_000, time.UTC)
+	g, _ := New(WithNode(63), WithClock(NewManualClock(ts)))
+	ids := g.GenerateN(nil, seqLimit)
+
+	min, max := RangeForTimes(ts, ts)
+
+	for _, id := range ids {
+		if id < min || id > max {
+			t.Fatalf("expected %d to be within [%d, %d]", id, min, max)
+		}
+	}
+
+	if min.Unix() != uint32(ts.Unix()) || min.Millis() != 456 || !max.Time().Equal(ts.Truncate(time.Millisecond)) {
+		t.Fatalf("expected both bounds at %v, got %d.%03d and %v", ts, min.Unix(), min.Millis(), max.Time())
+	}
+
+	if MinIDAt(ts.Add(time.Millisecond)) != max+1 {
+		t.Fatal("expected the next millisecond to start right after the max")
+	}
+
+	if MinIDAt(time.Unix(-1, 0)) != 0 {
+		t.Fatal("expected times before the epoch to clamp to 0")
+	}
+
+	if MaxIDAt(time.Unix(1<<33, 0)) != MaxIDAt(time.Unix(1<<32-1, 999_000_000)) {
+		t.Fatal("expected times beyond the layout to clamp to its end")
+	}
+}
+
+func TestLayoutRangeForTimes(t *testing.T) {
+	l := Layout{NodeBits: 10, SeqBits: 12, Epoch: NewEpoch(time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC))}
+	ts := time.Date(2025, 6, 1, 12, 30, 0, 0, time.UTC)
+	g, _ := New(WithLayout(l), WithNode(l.MaxNode()), WithClock(NewManualClock(ts)))
+
+	min, max := l.RangeForTimes(ts, ts.Add(time.Second))
+
+	if id := g.ID(); id < min || id > max {
+		t.Fatalf("expected %d to be within [%d, %d]", id, min, max)
+	}
+
+	if l.Unix(min) != ts.Unix() || !l.Time(max).Equal(ts.Add(time.Second)) {
+		t.Fatalf("unexpected bounds %d and %v", l.Unix(min), l.Time(max))
+	}
+}
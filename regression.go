@@ -0,0 +1,66 @@
+package hexid
+
+import (
+	"fmt"
+	"sync/atomic"
+	"time"
+)
+
+// clockMonitor keeps the high-water mark of a generator's clock readings, and counts how many
+// times the clock has moved backwards. All readings are in milliseconds since the generator's epoch.
+type clockMonitor struct {
+	wall        int64 // Highest reading so far
+	last        int64 // Previous reading
+	regressions uint64
+}
+
+// read reads the generator's clock and returns the time in nanoseconds since the generator's epoch
+// that the next ID should be based on, after applying the regression policy. A positive wait means
+// that the caller must sleep before reading again. When the clock is behind and the policy is
+// RegressionLogical, behind is true and the returned time is the high-water mark.
+func (m *clockMonitor) read(c *config) (now int64, behind bool, wait time.Duration, err error) {
+
+	// The previous readings must be loaded before the clock is read, so that a concurrent
+	// reading is never mistaken for a regression.
+	last := atomic.LoadInt64(&m.last)
+	wall := atomic.LoadInt64(&m.wall)
+	now = c.now()
+	nowMs := now / 1e6
+
+	if now < 0 {
+		return 0, false, 0, ErrBeforeEpoch
+	}
+
+	if now/1e9 >= 1<<c.Layout().secBits() {
+		return 0, false, 0, ErrLayoutExceeded
+	}
+
+	if nowMs != last && atomic.CompareAndSwapInt64(&m.last, last, nowMs) && nowMs < last {
+		atomic.AddUint64(&m.regressions, 1)
+	}
+
+	if nowMs >= wall {
+		if nowMs > wall {
+			atomic.CompareAndSwapInt64(&m.wall, wall, nowMs)
+		}
+
+		return now, false, 0, nil
+	}
+
+	switch c.regression {
+
+	case RegressionWait:
+		return 0, false, time.Duration(wall*1e6 - now), nil
+
+	case RegressionError:
+		return 0, false, 0, fmt.Errorf("%w by %s", ErrClockMovedBackwards, time.Duration(wall*1e6-now))
+
+	default:
+		return wall * 1e6, true, 0, nil
+	}
+}
+
+// Number of times the clock has been observed moving backwards.
+func (m *clockMonitor) count() uint64 {
+	return atomic.LoadUint64(&m.regressions)
+}
@@ -0,0 +1,184 @@
+package hexid
+
+import (
+	"errors"
+	"fmt"
+	"math/bits"
+	"math/rand/v2"
+	"runtime"
+	"slices"
+	"sync/atomic"
+	"time"
+)
+
+// Size of a CPU cache line, including adjacent-line prefetching.
+const cacheLine = 128
+
+// Largest number of shards of a ShardedGenerator.
+const maxShards = 64
+
+// Thread-safe ID generator that splits the sequence of each millisecond into disjoint sub-ranges,
+// one per shard, so that concurrent goroutines rarely contend on the same state. The total
+// throughput is the same as for AtomicGenerator, but each shard can only generate its share of the
+// layout's SeqLimit IDs per millisecond.
+type ShardedGenerator struct {
+	_       noCopy
+	monitor clockMonitor // Must be 64-bit aligned for atomic access
+	shards  []shard
+	mask    uint32
+	limit   uint32
+	config
+}
+
+type shard struct {
+	state uint64
+	_     [cacheLine - 8]byte
+}
+
+// Create a sharded ID generator with the provided options. The number of shards is rounded up to
+// a power of two, and defaults to GOMAXPROCS when zero. The generator is thread-safe.
+func NewShardedGenerator(shards int, opts ...Option) (*ShardedGenerator, error) {
+	if shards <= 0 {
+		shards = runtime.GOMAXPROCS(0)
+	}
+
+	if shards > maxShards {
+		return nil, fmt.Errorf("shards must be at most %d", maxShards)
+	}
+
+	shards = 1 << bits.Len(uint(shards-1))
+	cfg, err := newConfig(opts...)
+
+	if err != nil {
+		return nil, err
+	}
+
+	if cfg.monotonic && shards > 1 {
+		return nil, errors.New("monotonic IDs require a single shard")
+	}
+
+	if uint32(shards) > cfg.limit() {
+		return nil, fmt.Errorf("shards must be at most the layout's SeqLimit (%d)", cfg.limit())
+	}
+
+	g := &ShardedGenerator{
+		shards: make([]shard, shards),
+		mask:   uint32(shards - 1),
+		limit:  cfg.limit() / uint32(shards),
+		config: cfg,
+	}
+
+	states := make([]*uint64, shards)
+
+	for i := range g.shards {
+		states[i] = &g.shards[i].state
+	}
+
+	if err = g.restore(&g.monitor, states...); err != nil {
+		return nil, err
+	}
+
+	return g, nil
+}
+
+// Number of shards.
+func (g *ShardedGenerator) Shards() int {
+	return len(g.shards)
+}
+
+// Number of times the generator has observed the clock moving backwards.
+func (g *ShardedGenerator) Regressions() uint64 {
+	return g.monitor.count()
+}
+
+// Snapshot of the generator's stats. See WithStats. The peak and limit are per shard.
+func (g *ShardedGenerator) Stats() Stats {
+	return g.stats.snapshot(&g.monitor, g.limit)
+}
+
+// Atomically generates the next ID based on current time. Panics if TryID would return an error.
+func (g *ShardedGenerator) ID() ID {
+	id, err := g.TryID()
+
+	if err != nil {
+		panic(err)
+	}
+
+	return id
+}
+
+// Atomically generates the next ID based on current time from a random shard. See
+// (*AtomicGenerator).TryID.
+func (g *ShardedGenerator) TryID() (ID, error) {
+	s, base, err := g.reserve(1)
+
+	if err != nil {
+		return 0, err
+	}
+
+	return g.Layout().fromMilli(s.ms(), g.node, base+s.n()-1), nil
+}
+
+// Atomically fills dst with IDs based on current time from a random shard. Panics if TryFill
+// would return an error.
+func (g *ShardedGenerator) Fill(dst []ID) {
+	if err := g.TryFill(dst); err != nil {
+		panic(err)
+	}
+}
+
+// Atomically appends n IDs based on current time to dst, and returns the extended slice. See Fill.
+func (g *ShardedGenerator) GenerateN(dst []ID, n int) []ID {
+	dst = slices.Grow(dst, n)
+	g.Fill(dst[len(dst) : len(dst)+n])
+	return dst[:len(dst)+n]
+}
+
+// Atomically fills dst with IDs based on current time from a random shard, reserving their
+// sequence numbers in a single atomic operation. See (*AtomicGenerator).TryFill.
+func (g *ShardedGenerator) TryFill(dst []ID) error {
+	for len(dst) > 0 {
+		batch := dst[:min(len(dst), maxBatch)]
+		s, base, err := g.reserve(uint32(len(batch)))
+
+		if err != nil {
+			return err
+		}
+
+		s.fill(batch, g.Layout(), g.node, base, g.limit)
+		dst = dst[len(batch):]
+	}
+
+	return nil
+}
+
+// Reserves k IDs in a random shard, and returns the shard's new state along with the base of its
+// sequence sub-range.
+func (g *ShardedGenerator) reserve(k uint32) (s state, base uint32, err error) {
+	if g.lease != nil && !g.lease.Valid() {
+		return 0, 0, ErrLeaseLost
+	}
+
+	i := rand.Uint32() & g.mask
+	sh := &g.shards[i]
+
+	for {
+		var wait time.Duration
+		cur := state(atomic.LoadUint64(&sh.state))
+		s, wait, err = g.advance(cur, &g.monitor, k, g.limit)
+
+		if err != nil {
+			return
+		}
+
+		if wait > 0 {
+			g.sleep(wait)
+			continue
+		}
+
+		if atomic.CompareAndSwapUint64(&sh.state, uint64(cur), uint64(s)) {
+			g.stats.issue(s, k, g.limit)
+			return s, g.base + i*g.limit, nil
+		}
+	}
+}
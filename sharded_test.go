@@ -0,0 +1,122 @@
+package hexid
+
+import (
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestNewShardedGenerator(t *testing.T) {
+	tests := []struct {
+		shards, want int
+	}{
+		{1, 1},
+		{3, 4},
+		{8, 8},
+		{33, 64},
+		{64, 64},
+	}
+
+	for _, tt := range tests {
+		g, err := NewShardedGenerator(tt.shards)
+
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if g.Shards() != tt.want {
+			t.Errorf("expected %d shards for %d, got %d", tt.want, tt.shards, g.Shards())
+		}
+
+		if g.limit*uint32(g.Shards()) != seqLimit {
+			t.Errorf("shards of %d don't cover the whole sequence", g.limit)
+		}
+	}
+
+	if _, err := NewShardedGenerator(65); err == nil {
+		t.Error("expected an error")
+	}
+
+	if _, err := NewShardedGenerator(1, WithNode(0)); err == nil {
+		t.Error("expected an error")
+	}
+}
+
+func TestShardedGeneratorNoDuplicates(t *testing.T) {
+	const (
+		workers   = 8
+		perWorker = 20_000
+	)
+
+	ts := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	g, _ := NewShardedGenerator(4, WithClock(NewManualClock(ts)))
+	ids := make([][]ID, workers)
+	var wg sync.WaitGroup
+
+	for i := range ids {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+
+			if i%2 == 0 {
+				ids[i] = g.GenerateN(nil, perWorker)
+				return
+			}
+
+			ids[i] = make([]ID, perWorker)
+
+			for j := range ids[i] {
+				ids[i][j] = g.ID()
+			}
+		}()
+	}
+
+	wg.Wait()
+	seen := make(map[ID]struct{}, workers*perWorker)
+
+	for _, list := range ids {
+		for _, id := range list {
+			if _, ok := seen[id]; ok {
+				t.Fatalf("duplicate ID %s", id)
+			}
+
+			seen[id] = struct{}{}
+		}
+	}
+}
+
+func TestShardedGeneratorSubRange(t *testing.T) {
+	ts := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	g, _ := NewShardedGenerator(4, WithClock(NewManualClock(ts)), WithSequence(0))
+
+	for range 1000 {
+		id := g.ID()
+		i := uint32(id.Seq()) / g.limit
+
+		if s := state(g.shards[i].state); id.Time().UnixMilli() > s.ms() {
+			t.Fatalf("ID %s with seq %d doesn't belong to shard %d", id, id.Seq(), i)
+		}
+	}
+}
+
+func BenchmarkParallel(b *testing.B) {
+	b.Run("AtomicGenerator", func(b *testing.B) {
+		g, _ := New(WithOverflowPolicy(OverflowBorrow))
+
+		b.RunParallel(func(pb *testing.PB) {
+			for pb.Next() {
+				_ = g.ID()
+			}
+		})
+	})
+
+	b.Run("ShardedGenerator", func(b *testing.B) {
+		g, _ := NewShardedGenerator(0, WithOverflowPolicy(OverflowBorrow))
+
+		b.RunParallel(func(pb *testing.PB) {
+			for pb.Next() {
+				_ = g.ID()
+			}
+		})
+	})
+}
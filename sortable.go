@@ -0,0 +1,25 @@
+package hexid
+
+// A coder that doesn't scramble IDs, so that their encodings sort like the IDs themselves, i.e.
+// chronologically, and in issue order with WithMonotonic. Use it for IDs that are stored as text
+// where order matters, e.g. object storage keys or sort keys, and keep in mind that the encodings
+// reveal the timestamp and node of IDs. The encoding is hex, unless changed with WithEncoding.
+var SortableCoder = &Coder{mul: 1, inv: 1}
+
+var sortableBase32 = &Coder{mul: 1, inv: 1, enc: Base32}
+
+// Appends the sortable encoding of the ID to b, which is 13 characters of unscrambled Crockford
+// base32. See SortableCoder.
+func (id ID) AppendSortable(b []byte) []byte {
+	return sortableBase32.AppendEncode(b, id)
+}
+
+// Returns the sortable encoding of the ID. See AppendSortable.
+func (id ID) Sortable() string {
+	return sortableBase32.Encode(id)
+}
+
+// Decodes an ID from its sortable encoding. See AppendSortable.
+func IDFromSortable(str string) (ID, error) {
+	return sortableBase32.Decode(str)
+}
@@ -0,0 +1,64 @@
+package hexid
+
+import (
+	"fmt"
+	"slices"
+	"testing"
+	"time"
+)
+
+func ExampleID_Sortable() {
+	id := IDFromTime(time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC))
+
+	fmt.Println(id.Sortable()[:7]) // The timestamp is in the first characters
+
+	// Output: 37EJ2R0
+}
+
+func TestSortable(t *testing.T) {
+	clock := NewManualClock(time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC))
+	g, _ := New(WithClock(clock), WithMonotonic())
+	var ids []ID
+	var strs []string
+
+	for i := range 1000 {
+		clock.Advance(time.Duration(i%7) * time.Millisecond * 333)
+		id := g.ID()
+		ids = append(ids, id)
+		strs = append(strs, id.Sortable())
+	}
+
+	slices.Reverse(strs)
+	slices.Sort(strs)
+
+	for i, str := range strs {
+		id, err := IDFromSortable(str)
+
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if id != ids[i] {
+			t.Fatalf("expected %d at %d, got %d", ids[i], i, id)
+		}
+	}
+}
+
+func TestSortableCoder(t *testing.T) {
+	c, _ := SortableCoder.WithEncoding(Base62)
+	a, b := Generate(), Generate()
+
+	for _, c := range []*Coder{SortableCoder, c} {
+		if (c.Encode(a) < c.Encode(b)) != (a < b) {
+			t.Fatalf("expected %s and %s to sort like %d and %d", c.Encode(a), c.Encode(b), a, b)
+		}
+
+		if got, err := c.Decode(c.Encode(a)); err != nil || got != a {
+			t.Fatalf("expected %d, got %d (%v)", a, got, err)
+		}
+	}
+
+	if got, want := SortableCoder.Encode(a), fmt.Sprintf("%016x", uint64(a)); got != want {
+		t.Fatalf("expected %s, got %s", want, got)
+	}
+}
@@ -0,0 +1,76 @@
+package hexid
+
+import "time"
+
+const (
+	seqLimit  = 1 << 15 // Number of sequence numbers per millisecond per node in DefaultLayout
+	countBits = 22
+	countMask = 1<<countBits - 1
+)
+
+// state packs the millisecond a generator last issued an ID in together with the
+// number of IDs issued within that millisecond:
+//
+//	[63..22] = 42-bit milliseconds since the epoch (valid for 139 years)
+//	[21..0]  = 22-bit count
+type state uint64
+
+func newState(ms int64, n uint32) state {
+	return state(uint64(ms)<<countBits | uint64(n)&countMask)
+}
+
+// Millisecond of the last issued ID.
+func (s state) ms() int64 {
+	return int64(s >> countBits)
+}
+
+// Number of IDs issued within the millisecond.
+func (s state) n() uint32 {
+	return uint32(s & countMask)
+}
+
+// next returns the state after issuing k more IDs at the given time in nanoseconds, with
+// limit IDs per millisecond. The IDs are the k positions leading up to the returned state, which
+// may span several milliseconds when they don't fit within the current one. A positive wait
+// means that the caller must sleep before trying again.
+func (s state) next(now int64, k, limit uint32, overflow OverflowPolicy) (_ state, wait time.Duration, err error) {
+	ms, n := s.ms(), s.n()
+
+	if nowMs := now / 1e6; nowMs > ms {
+		ms, n = nowMs, 0
+	}
+
+	if n+k <= limit {
+		return newState(ms, n+k), 0, nil
+	}
+
+	switch overflow {
+
+	case OverflowError:
+		return s, 0, ErrSequenceExhausted
+
+	case OverflowWait:
+		if n == limit {
+			return s, time.Duration((ms+1)*1e6 - now), nil
+		}
+	}
+
+	// Spill over into the following milliseconds
+	last := uint64(ms)*uint64(limit) + uint64(n) + uint64(k) - 1
+	return newState(int64(last/uint64(limit)), uint32(last%uint64(limit))+1), 0, nil
+}
+
+// fill fills dst with the len(dst) IDs leading up to the state, with limit IDs per millisecond.
+func (s state) fill(dst []ID, l Layout, node uint16, base, limit uint32) {
+	first := uint64(s.ms())*uint64(limit) + uint64(s.n()) - uint64(len(dst))
+	ms, seq := int64(first/uint64(limit)), uint32(first%uint64(limit))
+
+	for i := range dst {
+		dst[i] = l.fromMilli(ms, node, base+seq)
+
+		if seq++; seq == limit {
+			ms++
+			seq = 0
+		}
+	}
+}
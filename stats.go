@@ -0,0 +1,103 @@
+package hexid
+
+import (
+	"expvar"
+	"log/slog"
+	"sync/atomic"
+)
+
+// Stats is a snapshot of a generator's counters. Apart from Regressions and SeqLimit, the counters
+// are only kept by generators created with WithStats.
+type Stats struct {
+	Issued       uint64 // IDs issued by ID, TryID and Fill
+	PeakPerMilli uint32 // Highest number of IDs issued within a single millisecond
+	SeqLimit     uint32 // Number of IDs that can be issued per millisecond
+	Exhaustions  uint64 // Times that an ID didn't fit within the sequence of its millisecond
+	Regressions  uint64 // Times that the clock has been observed moving backwards
+	Waits        uint64 // Times that the generator has slept, for either of the above reasons
+}
+
+// LogValue implements slog.LogValuer.
+func (s Stats) LogValue() slog.Value {
+	return slog.GroupValue(
+		slog.Uint64("issued", s.Issued),
+		slog.Uint64("peakPerMilli", uint64(s.PeakPerMilli)),
+		slog.Uint64("seqLimit", uint64(s.SeqLimit)),
+		slog.Uint64("exhaustions", s.Exhaustions),
+		slog.Uint64("regressions", s.Regressions),
+		slog.Uint64("waits", s.Waits),
+	)
+}
+
+// Publish stats as an expvar with the provided name, e.g. PublishStats("hexid", g.Stats) or
+// PublishStats("hexid", GlobalStats). Like expvar.Publish, it panics if the name is already in use.
+func PublishStats(name string, stats func() Stats) {
+	expvar.Publish(name, expvar.Func(func() any {
+		return stats()
+	}))
+}
+
+// Keep stats of the generated IDs, at the cost of a few atomic operations per ID. See Stats.
+func WithStats() Option {
+	return func(c *config) error {
+		c.stats = new(stats)
+		return nil
+	}
+}
+
+type stats struct {
+	issued      uint64
+	exhaustions uint64
+	waits       uint64
+	peak        uint32
+}
+
+// Records that k IDs have been issued, leading up to s with limit IDs per millisecond.
+func (st *stats) issue(s state, k, limit uint32) {
+	if st == nil {
+		return
+	}
+
+	atomic.AddUint64(&st.issued, uint64(k))
+	n := s.n()
+
+	// The IDs spilled over from at least one full millisecond
+	if k > n {
+		atomic.AddUint64(&st.exhaustions, 1)
+		n = limit
+	}
+
+	for {
+		peak := atomic.LoadUint32(&st.peak)
+
+		if n <= peak || atomic.CompareAndSwapUint32(&st.peak, peak, n) {
+			return
+		}
+	}
+}
+
+func (st *stats) exhaust() {
+	if st != nil {
+		atomic.AddUint64(&st.exhaustions, 1)
+	}
+}
+
+func (st *stats) wait() {
+	if st != nil {
+		atomic.AddUint64(&st.waits, 1)
+	}
+}
+
+func (st *stats) snapshot(m *clockMonitor, limit uint32) (s Stats) {
+	s.SeqLimit = limit
+	s.Regressions = m.count()
+
+	if st != nil {
+		s.Issued = atomic.LoadUint64(&st.issued)
+		s.PeakPerMilli = atomic.LoadUint32(&st.peak)
+		s.Exhaustions = atomic.LoadUint64(&st.exhaustions)
+		s.Waits = atomic.LoadUint64(&st.waits)
+	}
+
+	return
+}
@@ -0,0 +1,129 @@
+package hexid
+
+import (
+	"bytes"
+	"encoding/json"
+	"expvar"
+	"log/slog"
+	"strconv"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestStats(t *testing.T) {
+	ts := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	clock := NewManualClock(ts)
+	g, _ := New(WithStats(), WithClock(clock))
+
+	for range 10 {
+		g.ID()
+	}
+
+	g.Fill(make([]ID, seqLimit))
+
+	clock.Advance(-time.Second)
+	g.ID()
+
+	g.SetOverflowPolicy(OverflowError)
+	g.TryFill(make([]ID, seqLimit))
+
+	clock.Advance(time.Minute)
+	g.SetOverflowPolicy(OverflowWait)
+	g.Fill(make([]ID, seqLimit-1))
+	g.Fill(make([]ID, 1))
+
+	got := g.Stats()
+	want := Stats{
+		Issued:       10 + seqLimit + 1 + seqLimit,
+		PeakPerMilli: seqLimit,
+		SeqLimit:     seqLimit,
+		Exhaustions:  2,
+		Regressions:  1,
+		Waits:        0,
+	}
+
+	if got != want {
+		t.Fatalf("expected %+v, got %+v", want, got)
+	}
+
+	g.ID()
+
+	if got := g.Stats(); got.Waits != 1 || got.Exhaustions != 3 {
+		t.Fatalf("expected a wait after a full millisecond, got %+v", got)
+	}
+}
+
+func TestGeneratorStats(t *testing.T) {
+	clock := NewManualClock(time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC))
+	g, _ := NewGenerator()
+	g.SetClock(clock)
+	g.SetStats()
+
+	for range 10 {
+		g.ID()
+	}
+
+	clock.Advance(time.Millisecond)
+	g.Fill(make([]ID, 20))
+
+	// Issued within the millisecond of the batch, as the clock moved backwards
+	clock.Advance(-time.Second)
+	g.ID()
+
+	want := Stats{Issued: 31, PeakPerMilli: 21, SeqLimit: seqLimit, Regressions: 1}
+
+	if got := g.Stats(); got != want {
+		t.Fatalf("expected %+v, got %+v", want, got)
+	}
+}
+
+func TestStatsDisabled(t *testing.T) {
+	g, _ := New()
+	g.ID()
+
+	if got, want := g.Stats(), (Stats{SeqLimit: seqLimit}); got != want {
+		t.Fatalf("expected %+v, got %+v", want, got)
+	}
+}
+
+func TestStatsSharded(t *testing.T) {
+	g, _ := NewShardedGenerator(4, WithStats(), WithClock(NewManualClock(time.Now())))
+	g.Fill(make([]ID, 100))
+
+	if got := g.Stats(); got.Issued != 100 || got.PeakPerMilli != 100 || got.SeqLimit != seqLimit/4 {
+		t.Fatalf("unexpected stats %+v", got)
+	}
+}
+
+var publishRuns atomic.Uint64
+
+func TestPublishStats(t *testing.T) {
+	g, _ := New(WithStats())
+	g.ID()
+
+	// Names can only be published once per process, and tests can run more than once
+	name := "hexid_test_" + strconv.FormatUint(publishRuns.Add(1), 10)
+	PublishStats(name, g.Stats)
+
+	var got Stats
+
+	if err := json.Unmarshal([]byte(expvar.Get(name).String()), &got); err != nil {
+		t.Fatal(err)
+	}
+
+	if got != g.Stats() {
+		t.Fatalf("expected %+v, got %+v", g.Stats(), got)
+	}
+}
+
+func TestStatsLogValue(t *testing.T) {
+	var buf bytes.Buffer
+
+	slog.New(slog.NewTextHandler(&buf, nil)).Info("ids", "stats", Stats{Issued: 5, SeqLimit: seqLimit})
+
+	if !strings.Contains(buf.String(), "stats.issued=5 ") || !strings.Contains(buf.String(), "stats.seqLimit=32768 ") {
+		t.Fatalf("unexpected log line %q", buf.String())
+	}
+}
@@ -0,0 +1,193 @@
+package hexid
+
+import (
+	"database/sql"
+	"database/sql/driver"
+	"encoding"
+	"encoding/json"
+	"strings"
+)
+
+// A Prefix names a kind of ID, and is implemented by an empty struct:
+//
+//	type User struct{}
+//
+//	func (User) Prefix() string { return "usr" }
+//
+//	type UserID = hexid.TypedID[User]
+//
+// A kind can also choose the encoding of its IDs, instead of the encoding of the coder set by
+// SetCoder:
+//
+//	func (User) Encoding() hexid.Encoding { return hexid.Base58 }
+type Prefix interface {
+	Prefix() string
+}
+
+// Separator between the prefix and the encoded ID of a TypedID.
+const prefixSep = '_'
+
+// A TypedID is an ID of a specific kind, which is encoded with the prefix of the kind, e.g.
+// "usr_58c1fa4a4a00ca4f". Parsing fails when the prefix doesn't match, so IDs of different kinds
+// can't be mixed up by accident. In the database it's stored like a plain ID.
+type TypedID[P Prefix] ID
+
+var (
+	_ encoding.TextAppender    = TypedID[Prefix](0)
+	_ encoding.TextMarshaler   = TypedID[Prefix](0)
+	_ encoding.TextUnmarshaler = (*TypedID[Prefix])(nil)
+	_ json.Marshaler           = TypedID[Prefix](0)
+	_ json.Unmarshaler         = (*TypedID[Prefix])(nil)
+	_ sql.Scanner              = (*TypedID[Prefix])(nil)
+	_ driver.Valuer            = TypedID[Prefix](0)
+)
+
+// Atomically generates the next typed ID based on current time. Thread-safe.
+func GenerateTyped[P Prefix]() TypedID[P] {
+	return TypedID[P](Generate())
+}
+
+// Parses a typed ID with the prefix of P, e.g. "usr_58c1fa4a4a00ca4f".
+func ParseTypedID[P Prefix](str string) (TypedID[P], error) {
+	var p P
+	prefix := p.Prefix()
+
+	c, e := getCoder(), typedEncoding(p)
+	sep := prefix + string(prefixSep)
+
+	if !strings.HasPrefix(str, sep) {
+		return 0, prefixError(str, sep, sep+e.format(c.checked))
+	}
+
+	id, err := c.decodeEncoding(str[len(sep):], e)
+
+	if pe, ok := err.(*ParseError); ok {
+		pe.Input = str
+		pe.Format = sep + pe.Format
+
+		if pe.Offset >= 0 {
+			pe.Offset += len(sep)
+		}
+	}
+
+	return TypedID[P](id), err
+}
+
+// Error of a string that doesn't start with the prefix and separator sep.
+func prefixError(str, sep, format string) error {
+	for i := range min(len(str), len(sep)) {
+		if str[i] != sep[i] {
+			return &ParseError{Input: str, Offset: i, Format: format, Err: ErrInvalidCharacter}
+		}
+	}
+
+	return &ParseError{Input: str, Offset: -1, Format: format, Err: ErrInvalidLength}
+}
+
+// Encoding of the IDs of kind p.
+func typedEncoding(p Prefix) Encoding {
+	if e, ok := p.(interface{ Encoding() Encoding }); ok {
+		return e.Encoding()
+	}
+
+	return getCoder().Encoding()
+}
+
+// Prefix of the ID's kind.
+func (id TypedID[P]) Prefix() string {
+	var p P
+	return p.Prefix()
+}
+
+// Untyped ID.
+func (id TypedID[P]) ID() ID {
+	return ID(id)
+}
+
+func (id TypedID[P]) String() string {
+	b, _ := id.AppendText(make([]byte, 0, len(id.Prefix())+17))
+	return b2s(b)
+}
+
+// AppendText implements encoding.TextAppender.
+func (id TypedID[P]) AppendText(b []byte) ([]byte, error) {
+	b = append(b, id.Prefix()...)
+	b = append(b, prefixSep)
+
+	var p P
+	return getCoder().appendEncoding(b, ID(id), typedEncoding(p)), nil
+}
+
+// MarshalJSON implements json.Marshaler.
+func (id TypedID[P]) MarshalJSON() (b []byte, err error) {
+	if id == 0 {
+		return []byte{'n', 'u', 'l', 'l'}, nil
+	}
+
+	b = make([]byte, 0, len(id.Prefix())+19)
+	b = append(b, '"')
+	b, err = id.AppendText(b)
+	b = append(b, '"')
+
+	return
+}
+
+// UnmarshalJSON implements json.Unmarshaler.
+func (id *TypedID[P]) UnmarshalJSON(b []byte) (err error) {
+
+	// Parse string ID (with quotes)
+	if len(b) >= 2 && b[0] == '"' && b[len(b)-1] == '"' {
+		*id, err = ParseTypedID[P](b2s(b[1 : len(b)-1]))
+		return
+	}
+
+	// Parse null value (no quotes)
+	if len(b) == 4 && string(b) == "null" {
+		*id = 0
+		return
+	}
+
+	// Parse integer (no quote)
+	v, err := parseDecimal(b2s(b))
+	*id = TypedID[P](v)
+	return
+}
+
+// MarshalText implements encoding.TextMarshaler.
+func (id TypedID[P]) MarshalText() (text []byte, err error) {
+	return id.AppendText(make([]byte, 0, len(id.Prefix())+17))
+}
+
+// UnmarshalText implements encoding.TextUnmarshaler.
+func (id *TypedID[P]) UnmarshalText(text []byte) (err error) {
+	*id, err = ParseTypedID[P](b2s(text))
+	return
+}
+
+// Scan implements sql.Scanner. Strings must have the prefix of P.
+func (id *TypedID[P]) Scan(src any) (err error) {
+	switch v := src.(type) {
+	case string:
+		*id, err = ParseTypedID[P](v)
+	case []byte:
+		if len(v) == 8 {
+			err = (*ID)(id).Scan(v)
+		} else {
+			*id, err = ParseTypedID[P](b2s(v))
+		}
+	default:
+		err = (*ID)(id).Scan(src)
+	}
+
+	return
+}
+
+// Value implements driver.Valuer, and always stores the ID as a BIGINT, regardless of
+// SetValuerType, as an unprefixed string or bytes couldn't be scanned back into a TypedID.
+func (id TypedID[P]) Value() (driver.Value, error) {
+	if id == 0 {
+		return nil, nil
+	}
+
+	return ID(id).Int64(), nil
+}
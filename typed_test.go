@@ -0,0 +1,128 @@
+package hexid
+
+import (
+	"encoding/json"
+	"fmt"
+	"testing"
+
+	"github.com/webmafia/hexid/valuer"
+)
+
+type user struct{}
+
+func (user) Prefix() string { return "usr" }
+
+type order struct{}
+
+func (order) Prefix() string { return "ord" }
+
+func ExampleTypedID() {
+	id := TypedID[user](0x1234567890abcdef)
+
+	fmt.Println(id)
+
+	// Output: usr_bee99827fa22a2c1
+}
+
+func TestTypedID(t *testing.T) {
+	id := GenerateTyped[user]()
+	s := id.String()
+
+	if s[:4] != "usr_" || s[4:] != id.ID().String() {
+		t.Fatalf("unexpected string %q", s)
+	}
+
+	if got, err := ParseTypedID[user](s); err != nil || got != id {
+		t.Fatalf("expected %s, got %s (%v)", id, got, err)
+	}
+
+	for _, str := range []string{id.ID().String(), "ord_" + id.ID().String(), "usr", "usr_", "usrx" + id.ID().String()} {
+		if _, err := ParseTypedID[user](str); err == nil {
+			t.Errorf("expected %q to fail", str)
+		}
+	}
+
+	if _, err := ParseTypedID[order](s); err == nil {
+		t.Errorf("expected %q to fail as an order ID", s)
+	}
+}
+
+func TestTypedIDJSON(t *testing.T) {
+	type doc struct {
+		User  TypedID[user]  `json:"user"`
+		Order TypedID[order] `json:"order"`
+	}
+
+	in := doc{User: GenerateTyped[user]()}
+	b, err := json.Marshal(in)
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if want := fmt.Sprintf(`{"user":"%s","order":null}`, in.User); string(b) != want {
+		t.Fatalf("expected %s, got %s", want, b)
+	}
+
+	var out doc
+
+	if err := json.Unmarshal(b, &out); err != nil || out != in {
+		t.Fatalf("expected %+v, got %+v (%v)", in, out, err)
+	}
+
+	if err := json.Unmarshal(fmt.Appendf(nil, `{"order":"%s"}`, in.User), &out); err == nil {
+		t.Fatal("expected a user ID to be rejected as an order ID")
+	}
+
+	if err := json.Unmarshal(fmt.Appendf(nil, `{"user":%d}`, in.User.ID()), &out); err != nil || out.User != in.User {
+		t.Fatalf("expected %s, got %s (%v)", in.User, out.User, err)
+	}
+}
+
+func TestTypedIDSQL(t *testing.T) {
+	id := GenerateTyped[user]()
+	v, err := id.Value()
+
+	if err != nil || v != id.ID().Int64() {
+		t.Fatalf("expected %d, got %v (%v)", id.ID().Int64(), v, err)
+	}
+
+	for _, src := range []any{v, id.String(), []byte(id.String()), id.ID().Bytes()} {
+		var got TypedID[user]
+
+		if err := got.Scan(src); err != nil || got != id {
+			t.Fatalf("expected %s from %T, got %s (%v)", id, src, got, err)
+		}
+	}
+
+	var got TypedID[user]
+
+	if err := got.Scan(id.ID().String()); err == nil {
+		t.Fatal("expected an unprefixed string to be rejected")
+	}
+}
+
+func TestTypedIDStringValuer(t *testing.T) {
+	if err := SetValuerType(valuer.StringValuer); err != nil {
+		t.Fatal(err)
+	}
+
+	t.Cleanup(func() { SetValuerType(valuer.Int64Valuer) })
+
+	id := GenerateTyped[user]()
+	v, err := id.Value()
+
+	if err != nil || v != id.ID().Int64() {
+		t.Fatalf("expected %d, got %v (%v)", id.ID().Int64(), v, err)
+	}
+
+	var got TypedID[user]
+
+	if err := got.Scan(v); err != nil || got != id {
+		t.Fatalf("expected %s, got %s (%v)", id, got, err)
+	}
+
+	if v, err := TypedID[user](0).Value(); v != nil || err != nil {
+		t.Fatalf("expected NULL for the zero ID, got %v (%v)", v, err)
+	}
+}